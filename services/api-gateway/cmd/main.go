@@ -2,26 +2,60 @@ package main
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
 	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/config"
 	httphandler "github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/delivery/http"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/idempotency"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/openapi"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/ratelimit"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/realtime"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
 	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	"github.com/vasapolrittideah/moneylog-api/shared/auth"
+	"github.com/vasapolrittideah/moneylog-api/shared/database"
 	"github.com/vasapolrittideah/moneylog-api/shared/discovery"
+	"github.com/vasapolrittideah/moneylog-api/shared/healthcheck"
 	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"github.com/vasapolrittideah/moneylog-api/shared/resilience"
+	"github.com/vasapolrittideah/moneylog-api/shared/tracing"
 )
 
-const appShutdownTimout = 10 * time.Second
+const (
+	serviceName           = "api-gateway"
+	productionEnvironment = "production"
+	appShutdownTimout     = 10 * time.Second
+)
 
 func main() {
 	// ctx, cancel := context.WithCancel(context.Background())
 	// defer cancel()
 	logger := logger.Get()
 
+	tracingCfg := tracing.NewTracingConfig(logger)
+	tracerProvider, err := tracing.NewTracerProvider(context.Background(), serviceName, tracingCfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create tracer provider")
+	}
+	defer func() {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("Failed to shutdown tracer provider")
+		}
+	}()
+
 	consulCfg := discovery.NewConsulRegistryConfig(logger)
 	consulRegistry, err := discovery.NewConsulRegistry(consulCfg, logger)
 	if err != nil {
@@ -32,15 +66,116 @@ func main() {
 	authServiceClient, err := authclient.NewAuthServiceClient(
 		consulRegistry,
 		apiGatewayCfg.AuthService.Name,
+		resilience.NewClientConfig(logger),
 	)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to create auth client")
 	}
 
-	app := fiber.New()
+	readinessChecks := healthcheck.Checks{apiGatewayCfg.AuthService.Name: authServiceClient.Check}
+
+	idempotencyStore, mongoDB := newIdempotencyStore(&apiGatewayCfg.Idempotency, logger)
+	if mongoDB != nil {
+		readinessChecks["mongodb"] = mongoDB.Ping
+		defer func() {
+			if err := mongoDB.Disconnect(context.Background()); err != nil {
+				logger.Error().Err(err).Msg("Failed to disconnect from MongoDB")
+			}
+		}()
+	}
+
+	httpCfg := &apiGatewayCfg.HTTP
+	app := fiber.New(fiber.Config{
+		BodyLimit:               max(httpCfg.BodyLimit, httpCfg.AuthBodyLimit),
+		ErrorHandler:            response.ErrorHandler,
+		ProxyHeader:             httpCfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          httpCfg.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+
+	// Probes are registered before the middleware so that they are not traced, measured or logged.
+	healthHandler := httphandler.NewHealthHTTPHandler(readinessChecks, app)
+	healthHandler.RegisterRoutes()
 
-	authHandler := httphandler.NewAuthHTTPHandler(authServiceClient, app, logger)
-	authHandler.RegisterRoutes()
+	app.Use(otelfiber.Middleware())
+	app.Use(middleware.NewMetricsMiddleware())
+	app.Use(middleware.NewRequestIDMiddleware(logger))
+	app.Use(middleware.NewSecurityHeadersMiddleware(httpCfg.HSTSMaxAge, httpCfg.CSP))
+	app.Use(middleware.NewCORSMiddleware(httpCfg.AllowedOrigins, httpCfg.CORSMaxAge))
+	app.Use(middleware.NewWebSocketOriginMiddleware(httpCfg.AllowedOrigins))
+	app.Use(middleware.NewCompressionMiddleware(httpCfg.CompressionLevel))
+
+	jwtAuthenticator := auth.NewJWTAuthenticator(
+		apiGatewayCfg.Token.Issuer,
+		apiGatewayCfg.Token.Issuer,
+	)
+	cookies := authcookie.NewTransport(authcookie.Options{
+		Enabled:  apiGatewayCfg.AuthCookie.Enabled,
+		Domain:   apiGatewayCfg.AuthCookie.Domain,
+		Secure:   apiGatewayCfg.AuthCookie.Secure,
+		SameSite: apiGatewayCfg.AuthCookie.SameSite,
+	})
+	authMiddleware := middleware.NewAuthMiddleware(jwtAuthenticator, apiGatewayCfg.Token.AccessTokenSecret, cookies)
+
+	rateLimitStore := newRateLimitStore(&apiGatewayCfg.RateLimit)
+	authLimit := ratelimit.Limit{Burst: apiGatewayCfg.RateLimit.AuthBurst, Period: apiGatewayCfg.RateLimit.AuthPeriod}
+	authRateLimit := middleware.NewRateLimitMiddleware(rateLimitStore, middleware.RateLimitPolicy{
+		Name:  "auth",
+		Read:  authLimit,
+		Write: authLimit,
+		Key:   middleware.RateLimitByIP,
+	})
+	userRateLimit := middleware.NewRateLimitMiddleware(rateLimitStore, middleware.RateLimitPolicy{
+		Name:  "user",
+		Read:  ratelimit.Limit{Burst: apiGatewayCfg.RateLimit.ReadBurst, Period: apiGatewayCfg.RateLimit.ReadPeriod},
+		Write: ratelimit.Limit{Burst: apiGatewayCfg.RateLimit.WriteBurst, Period: apiGatewayCfg.RateLimit.WritePeriod},
+		Key:   middleware.RateLimitByUserID,
+	})
+
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyStore, middleware.IdempotencyPolicy{
+		TTL:         apiGatewayCfg.Idempotency.TTL,
+		LockTimeout: apiGatewayCfg.Idempotency.LockTimeout,
+	})
+
+	hub := realtime.NewHub(authServiceClient.Client.SubscribeEvents, realtime.Options{
+		HeartbeatInterval:     apiGatewayCfg.Realtime.HeartbeatInterval,
+		MaxConnectionsPerUser: apiGatewayCfg.Realtime.MaxConnectionsPerUser,
+		ReconnectDelay:        apiGatewayCfg.Realtime.ReconnectDelay,
+	})
+
+	table := proxy.NewTable()
+	httphandler.RegisterRoutes(app, table, authServiceClient, hub, cookies, httphandler.RouteMiddleware{
+		Auth:          authMiddleware,
+		AuthRateLimit: authRateLimit,
+		UserRateLimit: userRateLimit,
+		AuthBodyLimit: middleware.NewBodyLimitMiddleware(httpCfg.AuthBodyLimit),
+		UserBodyLimit: middleware.NewBodyLimitMiddleware(httpCfg.BodyLimit),
+		Idempotency:   idempotencyMiddleware,
+		CSRF:          middleware.NewCSRFMiddleware(cookies),
+	}, httphandler.BatchOptions{
+		MaxRequests: httpCfg.BatchMaxRequests,
+		ProxyHeader: httpCfg.ProxyHeader,
+	}, httphandler.GraphQLOptions{
+		MaxDepth: httpCfg.GraphQLMaxDepth,
+		MaxItems: httpCfg.GraphQLMaxItems,
+	}, httphandler.UnversionedOptions{
+		Deprecated: apiGatewayCfg.Versioning.UnversionedDeprecated,
+		Sunset:     apiGatewayCfg.Versioning.UnversionedSunset,
+	})
+
+	if apiGatewayCfg.Environment != productionEnvironment {
+		openapi.Register(app, openapi.Generate(table.Operations()))
+	}
+
+	metricsCfg := metrics.NewMetricsConfig(logger)
+	metricsServer := metrics.NewServer(metricsCfg)
+	go func() {
+		logger.Info().Str("address", metricsCfg.Addr).Msg("Starting metrics server")
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Msg("Failed to start metrics server")
+		}
+	}()
 
 	serverErrors := make(chan error, 1)
 
@@ -62,8 +197,47 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), appShutdownTimout)
 		defer cancel()
 
+		// Event streams stay open until the clients disconnect, so they are ended first.
+		hub.Close()
 		if err := app.ShutdownWithContext(ctx); err != nil {
 			logger.Error().Err(err).Msg("Failed to shutdown server")
 		}
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to shutdown metrics server")
+		}
+	}
+}
+
+// newRateLimitStore creates the rate limit counter store selected in the configuration.
+func newRateLimitStore(cfg *config.RateLimitConfig) ratelimit.Store {
+	if cfg.Store == "redis" {
+		return ratelimit.NewRedisStore(redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+		}))
+	}
+
+	return ratelimit.NewMemoryStore()
+}
+
+// newIdempotencyStore creates the idempotency store selected in the configuration, along with the
+// MongoDB connection it uses, if any.
+func newIdempotencyStore(cfg *config.IdempotencyConfig, logger *zerolog.Logger) (idempotency.Store, *database.MongoDB) {
+	if cfg.Store == "memory" {
+		return idempotency.NewMemoryStore(), nil
+	}
+
+	ctx := context.Background()
+
+	mongoDB := database.NewMongoDB(database.NewMongoConfig(logger), logger)
+	if err := mongoDB.Connect(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to connect to MongoDB")
 	}
+
+	store, err := idempotency.NewMongoStore(ctx, mongoDB.GetDatabase())
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create idempotency store")
+	}
+
+	return store, mongoDB
 }
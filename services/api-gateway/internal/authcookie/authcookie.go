@@ -0,0 +1,132 @@
+// Package authcookie implements the cookie token transport used by the web app. Instead of returning
+// tokens in the response body, where the app would have to keep them in script-readable storage, the
+// gateway sets them as HttpOnly cookies and protects cookie-authenticated requests with a
+// double-submit CSRF token.
+package authcookie
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/shared/auth"
+)
+
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFTokenCookie    = "csrf_token"
+
+	// HeaderCSRFToken is the header cookie-authenticated requests echo the CSRF cookie in.
+	HeaderCSRFToken = "X-CSRF-Token"
+	// HeaderTokenTransport is the header clients set to TransportCookie to receive tokens as cookies.
+	HeaderTokenTransport = "X-Token-Transport"
+	TransportCookie      = "cookie"
+
+	// RefreshPath is the path of the refresh route below the API version prefix. The refresh token
+	// cookie is only sent to it.
+	RefreshPath = "/auth/refresh"
+)
+
+// expired is the expiry time of cleared cookies. Any time in the past makes browsers delete them.
+var expired = time.Unix(0, 0)
+
+// Options describes the attributes of the cookies.
+type Options struct {
+	Enabled  bool
+	Domain   string
+	Secure   bool
+	SameSite string
+}
+
+// Transport reads and writes the token cookies. When it is disabled, tokens are never set as
+// cookies and cookies sent by clients are ignored.
+type Transport struct {
+	opts Options
+}
+
+// NewTransport creates a cookie token transport.
+func NewTransport(opts Options) *Transport {
+	return &Transport{opts: opts}
+}
+
+// Requested reports whether the client asked to receive its tokens as cookies.
+func (t *Transport) Requested(c *fiber.Ctx) bool {
+	return t.opts.Enabled && strings.EqualFold(c.Get(HeaderTokenTransport), TransportCookie)
+}
+
+// SetTokens sets the access and refresh token cookies, together with a new CSRF token that is
+// also returned in the X-CSRF-Token response header. The refresh token cookie is scoped to
+// refreshPath. The cookies expire with their tokens.
+func (t *Transport) SetTokens(c *fiber.Ctx, accessToken, refreshToken, refreshPath string) {
+	refreshExpiresAt := auth.ExpiresAt(refreshToken)
+
+	c.Cookie(t.cookie(AccessTokenCookie, accessToken, "/", auth.ExpiresAt(accessToken), true))
+	c.Cookie(t.cookie(RefreshTokenCookie, refreshToken, refreshPath, refreshExpiresAt, true))
+
+	csrfToken := rand.Text()
+	c.Cookie(t.cookie(CSRFTokenCookie, csrfToken, "/", refreshExpiresAt, false))
+	c.Set(HeaderCSRFToken, csrfToken)
+}
+
+// ClearTokens expires the access, refresh and CSRF token cookies, for clients that signed out. The
+// refresh token cookie is matched by refreshPath, which must be the path it was set with.
+func (t *Transport) ClearTokens(c *fiber.Ctx, refreshPath string) {
+	if !t.opts.Enabled {
+		return
+	}
+
+	c.Cookie(t.cookie(AccessTokenCookie, "", "/", expired, true))
+	c.Cookie(t.cookie(RefreshTokenCookie, "", refreshPath, expired, true))
+	c.Cookie(t.cookie(CSRFTokenCookie, "", "/", expired, false))
+}
+
+// AccessToken returns the access token cookie, or an empty string when there is none.
+func (t *Transport) AccessToken(c *fiber.Ctx) string {
+	if !t.opts.Enabled {
+		return ""
+	}
+
+	return c.Cookies(AccessTokenCookie)
+}
+
+// RefreshToken returns the refresh token cookie, or an empty string when there is none.
+func (t *Transport) RefreshToken(c *fiber.Ctx) string {
+	if !t.opts.Enabled {
+		return ""
+	}
+
+	return c.Cookies(RefreshTokenCookie)
+}
+
+// Authenticated reports whether the request carries a token cookie that may authenticate it.
+func (t *Transport) Authenticated(c *fiber.Ctx) bool {
+	return t.AccessToken(c) != "" || t.RefreshToken(c) != ""
+}
+
+// ValidCSRF reports whether the X-CSRF-Token header matches the CSRF cookie. A cross-site page can
+// make the browser send the cookies, but it can neither read the CSRF cookie nor set the header.
+func (t *Transport) ValidCSRF(c *fiber.Ctx) bool {
+	cookie := c.Cookies(CSRFTokenCookie)
+	header := c.Get(HeaderCSRFToken)
+	if cookie == "" || header == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func (t *Transport) cookie(name, value, path string, expires time.Time, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   t.opts.Domain,
+		Expires:  expires,
+		Secure:   t.opts.Secure,
+		HTTPOnly: httpOnly,
+		SameSite: t.opts.SameSite,
+	}
+}
@@ -0,0 +1,198 @@
+package authcookie_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+)
+
+func newToken(t *testing.T, expiresAt time.Time) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": expiresAt.Unix()}).
+		SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return token
+}
+
+func TestSetTokens(t *testing.T) {
+	cookies := authcookie.NewTransport(authcookie.Options{Enabled: true, Secure: true, SameSite: "Strict"})
+	refreshExpiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	accessToken := newToken(t, time.Now().Add(time.Hour))
+	refreshToken := newToken(t, refreshExpiresAt)
+	refreshPath := "/v1" + authcookie.RefreshPath
+
+	app := fiber.New()
+	app.Post("/login", func(c *fiber.Ctx) error {
+		if !cookies.Requested(c) {
+			return c.SendStatus(http.StatusBadRequest)
+		}
+		cookies.SetTokens(c, accessToken, refreshToken, refreshPath)
+		return c.SendStatus(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set(authcookie.HeaderTokenTransport, authcookie.TransportCookie)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	got := make(map[string]*http.Cookie)
+	for _, cookie := range resp.Cookies() {
+		got[cookie.Name] = cookie
+	}
+
+	access := got[authcookie.AccessTokenCookie]
+	if access == nil || access.Value != accessToken || !access.HttpOnly || !access.Secure || access.Path != "/" {
+		t.Errorf("access token cookie = %+v", access)
+	}
+	if access != nil && access.SameSite != http.SameSiteStrictMode {
+		t.Errorf("access token cookie SameSite = %v, want Strict", access.SameSite)
+	}
+
+	refresh := got[authcookie.RefreshTokenCookie]
+	if refresh == nil || refresh.Value != refreshToken || !refresh.HttpOnly || refresh.Path != refreshPath {
+		t.Errorf("refresh token cookie = %+v", refresh)
+	}
+	if refresh != nil && !refresh.Expires.Equal(refreshExpiresAt) {
+		t.Errorf("refresh token cookie expires at %v, want %v", refresh.Expires, refreshExpiresAt)
+	}
+
+	csrf := got[authcookie.CSRFTokenCookie]
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly {
+		t.Errorf("CSRF token cookie = %+v", csrf)
+	}
+	if csrf != nil && resp.Header.Get(authcookie.HeaderCSRFToken) != csrf.Value {
+		t.Errorf("CSRF token header = %q, want %q", resp.Header.Get(authcookie.HeaderCSRFToken), csrf.Value)
+	}
+}
+
+func TestClearTokens(t *testing.T) {
+	cookies := authcookie.NewTransport(authcookie.Options{Enabled: true, Secure: true, SameSite: "Strict"})
+	refreshPath := "/v1" + authcookie.RefreshPath
+
+	app := fiber.New()
+	app.Post("/logout", func(c *fiber.Ctx) error {
+		cookies.ClearTokens(c, refreshPath)
+		return c.SendStatus(http.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/logout", nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	paths := map[string]string{
+		authcookie.AccessTokenCookie:  "/",
+		authcookie.RefreshTokenCookie: refreshPath,
+		authcookie.CSRFTokenCookie:    "/",
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Value != "" || !cookie.Expires.Before(time.Now()) || cookie.Path != paths[cookie.Name] {
+			t.Errorf("cookie %s was not cleared: %+v", cookie.Name, cookie)
+		}
+		delete(paths, cookie.Name)
+	}
+	for name := range paths {
+		t.Errorf("cookie %s was not cleared", name)
+	}
+}
+
+func TestRequested(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		transport string
+		want      bool
+	}{
+		{name: "cookie transport", enabled: true, transport: "Cookie", want: true},
+		{name: "bearer client", enabled: true, transport: "", want: false},
+		{name: "disabled", enabled: false, transport: authcookie.TransportCookie, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies := authcookie.NewTransport(authcookie.Options{Enabled: tt.enabled})
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if cookies.Requested(c) {
+					return c.SendStatus(http.StatusOK)
+				}
+				return c.SendStatus(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(authcookie.HeaderTokenTransport, tt.transport)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.StatusCode == http.StatusOK; got != tt.want {
+				t.Errorf("Requested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		want   bool
+	}{
+		{name: "matching", cookie: "token", header: "token", want: true},
+		{name: "mismatch", cookie: "token", header: "other", want: false},
+		{name: "missing header", cookie: "token", header: "", want: false},
+		{name: "missing cookie", cookie: "", header: "token", want: false},
+	}
+
+	cookies := authcookie.NewTransport(authcookie.Options{Enabled: true})
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		if cookies.ValidCSRF(c) {
+			return c.SendStatus(http.StatusOK)
+		}
+		return c.SendStatus(http.StatusForbidden)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: authcookie.CSRFTokenCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(authcookie.HeaderCSRFToken, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.StatusCode == http.StatusOK; got != tt.want {
+				t.Errorf("ValidCSRF() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/caarlos0/env/v11"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/rs/zerolog"
 )
 
@@ -9,17 +13,138 @@ type APIGatewayConfig struct {
 	Environment string `env:"ENVIRONMENT"`
 	Addr        string `env:"API_GATEWAY_ADDR"`
 	AuthService AuthServiceConfig
+	Token       TokenConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	AuthCookie  AuthCookieConfig
+	HTTP        HTTPConfig
+	Realtime    RealtimeConfig
+	Versioning  VersioningConfig
 }
 
 type AuthServiceConfig struct {
 	Name string `env:"AUTH_SERVICE_NAME"`
 }
 
+type TokenConfig struct {
+	AccessTokenSecret string `env:"ACCESS_TOKEN_SECRET"`
+	Issuer            string `env:"TOKEN_ISSUER"`
+}
+
+type RateLimitConfig struct {
+	Store         string        `env:"RATE_LIMIT_STORE"          envDefault:"memory"`
+	RedisAddr     string        `env:"RATE_LIMIT_REDIS_ADDR"`
+	RedisPassword string        `env:"RATE_LIMIT_REDIS_PASSWORD"`
+	AuthBurst     int           `env:"RATE_LIMIT_AUTH_BURST"     envDefault:"10"`
+	AuthPeriod    time.Duration `env:"RATE_LIMIT_AUTH_PERIOD"    envDefault:"1m"`
+	ReadBurst     int           `env:"RATE_LIMIT_READ_BURST"     envDefault:"120"`
+	ReadPeriod    time.Duration `env:"RATE_LIMIT_READ_PERIOD"    envDefault:"1m"`
+	WriteBurst    int           `env:"RATE_LIMIT_WRITE_BURST"    envDefault:"30"`
+	WritePeriod   time.Duration `env:"RATE_LIMIT_WRITE_PERIOD"   envDefault:"1m"`
+}
+
+// validate checks that every bucket holds at least one token and refills over at least a
+// millisecond, the resolution the buckets are refilled at.
+func (c RateLimitConfig) validate() error {
+	limits := []struct {
+		name   string
+		burst  int
+		period time.Duration
+	}{
+		{name: "AUTH", burst: c.AuthBurst, period: c.AuthPeriod},
+		{name: "READ", burst: c.ReadBurst, period: c.ReadPeriod},
+		{name: "WRITE", burst: c.WriteBurst, period: c.WritePeriod},
+	}
+	for _, limit := range limits {
+		if limit.burst < 1 {
+			return fmt.Errorf("RATE_LIMIT_%s_BURST must be at least 1, got %d", limit.name, limit.burst)
+		}
+		if limit.period < time.Millisecond {
+			return fmt.Errorf("RATE_LIMIT_%s_PERIOD must be at least 1ms, got %s", limit.name, limit.period)
+		}
+	}
+
+	return nil
+}
+
+type IdempotencyConfig struct {
+	Store       string        `env:"IDEMPOTENCY_STORE"        envDefault:"mongo"`
+	TTL         time.Duration `env:"IDEMPOTENCY_TTL"          envDefault:"24h"`
+	LockTimeout time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT" envDefault:"1m"`
+}
+
+type AuthCookieConfig struct {
+	Enabled  bool   `env:"AUTH_COOKIE_ENABLED"   envDefault:"false"`
+	Domain   string `env:"AUTH_COOKIE_DOMAIN"`
+	Secure   bool   `env:"AUTH_COOKIE_SECURE"    envDefault:"true"`
+	SameSite string `env:"AUTH_COOKIE_SAME_SITE" envDefault:"Lax"`
+}
+
+// HTTPConfig hardens the gateway's HTTP server. BodyLimit applies to the app, and AuthBodyLimit
+// lowers it for the unauthenticated /auth routes. X-Forwarded-For is only trusted from TrustedProxies,
+// which may contain IP addresses and CIDR ranges.
+type HTTPConfig struct {
+	AllowedOrigins []string      `env:"CORS_ALLOWED_ORIGINS"    envSeparator:","`
+	CORSMaxAge     time.Duration `env:"CORS_MAX_AGE"            envDefault:"1h"`
+	HSTSMaxAge     time.Duration `env:"HSTS_MAX_AGE"            envDefault:"8760h"`
+	CSP            string        `env:"CONTENT_SECURITY_POLICY" envDefault:"default-src 'none'; frame-ancestors 'none'"`
+	BodyLimit      int           `env:"BODY_LIMIT"              envDefault:"1048576"`
+	AuthBodyLimit  int           `env:"AUTH_BODY_LIMIT"         envDefault:"16384"`
+	TrustedProxies []string      `env:"TRUSTED_PROXIES"         envSeparator:","`
+	ProxyHeader    string        `env:"PROXY_HEADER"            envDefault:"X-Forwarded-For"`
+
+	// CompressionLevel is a level of Fiber's compress middleware: -1 disables compression, 0 is the
+	// default, 1 favors speed and 2 favors size.
+	CompressionLevel compress.Level `env:"COMPRESSION_LEVEL" envDefault:"0"`
+	// BatchMaxRequests is the number of sub-requests a batch may hold.
+	BatchMaxRequests int `env:"BATCH_MAX_REQUESTS" envDefault:"10"`
+	// GraphQLMaxDepth and GraphQLMaxItems limit how deeply GraphQL queries nest and how many list
+	// items they load, counting each page at its requested size.
+	GraphQLMaxDepth int `env:"GRAPHQL_MAX_DEPTH" envDefault:"8"`
+	GraphQLMaxItems int `env:"GRAPHQL_MAX_ITEMS" envDefault:"1000"`
+}
+
+// RealtimeConfig tunes the realtime event streams. MaxConnectionsPerUser applies to each gateway
+// instance, and ReconnectDelay is the first delay before a broken backend stream is reopened.
+type RealtimeConfig struct {
+	HeartbeatInterval     time.Duration `env:"REALTIME_HEARTBEAT_INTERVAL"       envDefault:"25s"`
+	MaxConnectionsPerUser int           `env:"REALTIME_MAX_CONNECTIONS_PER_USER" envDefault:"5"`
+	ReconnectDelay        time.Duration `env:"REALTIME_RECONNECT_DELAY"          envDefault:"1s"`
+}
+
+// VersioningConfig schedules the retirement of the unversioned routes, the aliases of /v1 that
+// predate API versioning, as RFC 3339 timestamps. The defaults are the dates the aliases were
+// deprecated with. UnversionedSunset is announced to clients in the Sunset header, so it should only
+// be moved later, never earlier.
+type VersioningConfig struct {
+	UnversionedDeprecated time.Time `env:"UNVERSIONED_DEPRECATED" envDefault:"2026-10-19T00:00:00Z"`
+	UnversionedSunset     time.Time `env:"UNVERSIONED_SUNSET"     envDefault:"2027-04-30T00:00:00Z"`
+}
+
+// validate checks that the unversioned routes are deprecated before they are sunset.
+func (c VersioningConfig) validate() error {
+	if !c.UnversionedSunset.After(c.UnversionedDeprecated) {
+		return fmt.Errorf(
+			"UNVERSIONED_SUNSET must be after UNVERSIONED_DEPRECATED, got %s and %s",
+			c.UnversionedSunset.Format(time.RFC3339),
+			c.UnversionedDeprecated.Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
 func NewAPIGatewayConfig(logger *zerolog.Logger) *APIGatewayConfig {
 	cfg, err := env.ParseAs[APIGatewayConfig]()
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to parse env")
 	}
+	if err := cfg.RateLimit.validate(); err != nil {
+		logger.Fatal().Err(err).Msg("Invalid rate limit configuration")
+	}
+	if err := cfg.Versioning.validate(); err != nil {
+		logger.Fatal().Err(err).Msg("Invalid versioning configuration")
+	}
 
 	return &cfg
 }
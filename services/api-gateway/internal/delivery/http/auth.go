@@ -1,115 +1,236 @@
 package http
 
 import (
-	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
 	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
-	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/validator"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
 	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
-	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/auth"
 	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
-	"google.golang.org/grpc/status"
 )
 
+const tokenTypeBearer = "Bearer"
+
 type AuthHTTPHandler struct {
 	authServiceClient *authclient.AuthServiceClient
+	cookies           *authcookie.Transport
 	router            fiber.Router
-	logger            *zerolog.Logger
 }
 
 func NewAuthHTTPHandler(
 	authServiceClient *authclient.AuthServiceClient,
+	cookies *authcookie.Transport,
 	router fiber.Router,
-	logger *zerolog.Logger,
 ) *AuthHTTPHandler {
 	return &AuthHTTPHandler{
 		authServiceClient: authServiceClient,
+		cookies:           cookies,
 		router:            router,
-		logger:            logger,
 	}
 }
 
-func (h *AuthHTTPHandler) RegisterRoutes() {
-	router := h.router.Group("/auth")
-	router.Post("/login", h.login)
-	router.Post("/signup", h.signUp)
+// RegisterRoutes registers the v1 routes, which return the tokens in the payload of each route.
+func (h *AuthHTTPHandler) RegisterRoutes(table *proxy.Table) {
+	client := h.authServiceClient.Client
+
+	table.Register(h.router.Group("/auth"),
+		proxy.NewRoute(fiber.MethodPost, "/login", client.Login, toLoginRequest, h.toLoginResponse),
+		proxy.NewRoute(fiber.MethodPost, "/signup", client.SignUp, toSignUpRequest, h.toSignUpResponse),
+		proxy.NewRoute(
+			fiber.MethodPost,
+			"/refresh",
+			client.RefreshToken,
+			h.toRefreshTokenRequest,
+			h.toRefreshTokenResponse,
+		),
+		h.revokeSuspiciousLoginRoute(),
+		h.requestPasswordResetRoute(),
+		h.resetPasswordRoute(),
+	)
+}
+
+// RegisterV2Routes registers the v2 routes, which return every pair of tokens as a
+// payload.TokenResponse.
+func (h *AuthHTTPHandler) RegisterV2Routes(table *proxy.Table) {
+	client := h.authServiceClient.Client
+
+	table.Register(h.router.Group("/auth"),
+		proxy.NewRoute(fiber.MethodPost, "/login", client.Login, toLoginRequest, h.toLoginTokenResponse),
+		proxy.NewRoute(fiber.MethodPost, "/signup", client.SignUp, toSignUpRequest, h.toSignUpTokenResponse),
+		proxy.NewRoute(
+			fiber.MethodPost,
+			"/refresh",
+			client.RefreshToken,
+			h.toRefreshTokenRequest,
+			h.toRefreshTokenTokenResponse,
+		),
+		h.revokeSuspiciousLoginRoute(),
+		h.requestPasswordResetRoute(),
+		h.resetPasswordRoute(),
+	)
+}
+
+func (h *AuthHTTPHandler) revokeSuspiciousLoginRoute() proxy.Endpoint {
+	return proxy.NewCommand(
+		fiber.MethodPost,
+		"/login-alerts/revoke",
+		h.authServiceClient.Client.RevokeSuspiciousLogin,
+		toRevokeSuspiciousLoginRequest,
+	)
 }
 
-func (h *AuthHTTPHandler) login(c *fiber.Ctx) error {
-	var req payload.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(
-			contract.NewErrorResponse(contract.ErrorCodeValidation, err.Error()),
-		)
+func (h *AuthHTTPHandler) requestPasswordResetRoute() proxy.Endpoint {
+	return proxy.NewCommand(
+		fiber.MethodPost,
+		"/password-reset",
+		h.authServiceClient.Client.RequestPasswordReset,
+		toRequestPasswordResetRequest,
+	)
+}
+
+func (h *AuthHTTPHandler) resetPasswordRoute() proxy.Endpoint {
+	return proxy.NewCommand(
+		fiber.MethodPost,
+		"/password-reset/confirm",
+		h.authServiceClient.Client.ResetPassword,
+		toResetPasswordRequest,
+	)
+}
+
+func toLoginRequest(c *fiber.Ctx, req *payload.LoginRequest) *authpbv1.LoginRequest {
+	return &authpbv1.LoginRequest{
+		Email:     req.Email,
+		Password:  req.Password,
+		IpAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
 	}
+}
 
-	if errs := validator.ValidateStruct(req); len(errs) != 0 {
-		return c.Status(http.StatusBadRequest).JSON(
-			contract.NewValidationErrorResponse(errs),
-		)
+func (h *AuthHTTPHandler) toLoginResponse(c *fiber.Ctx, resp *authpbv1.LoginResponse) *payload.LoginResponse {
+	if h.setTokenCookies(c, resp.GetAccessToken(), resp.GetRefreshToken()) {
+		return &payload.LoginResponse{}
 	}
 
-	grpcResp, err := h.authServiceClient.Client.Login(c.Context(), &authpbv1.LoginRequest{
-		Email:    req.Email,
-		Password: req.Password,
-	})
-	if err != nil {
-		st := status.Convert(err)
-		h.logger.Error().Err(st.Err()).Msg("Failed to login")
+	return &payload.LoginResponse{
+		AccessToken:  resp.GetAccessToken(),
+		RefreshToken: resp.GetRefreshToken(),
+	}
+}
 
-		errorCode := contract.ErrorCodeFromGRPCCode(st.Code())
-		httpStatus := contract.HTTPStatusFromGRPCCode(st.Code())
+func toSignUpRequest(c *fiber.Ctx, req *payload.SignUpRequest) *authpbv1.SignUpRequest {
+	return &authpbv1.SignUpRequest{
+		Email:     req.Email,
+		Password:  req.Password,
+		FullName:  req.FullName,
+		IpAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
 
-		return c.Status(httpStatus).JSON(
-			contract.NewErrorResponse(errorCode, "failed to login"),
-		)
+func (h *AuthHTTPHandler) toSignUpResponse(c *fiber.Ctx, resp *authpbv1.SignUpResponse) *payload.SignUpResponse {
+	if h.setTokenCookies(c, resp.GetAccessToken(), resp.GetRefreshToken()) {
+		return &payload.SignUpResponse{}
 	}
 
-	apiResp := contract.NewSuccessResponse(&payload.LoginResponse{
-		AccessToken:  grpcResp.GetAccessToken(),
-		RefreshToken: grpcResp.GetRefreshToken(),
-	})
+	return &payload.SignUpResponse{
+		AccessToken:  resp.GetAccessToken(),
+		RefreshToken: resp.GetRefreshToken(),
+	}
+}
+
+func (h *AuthHTTPHandler) toRefreshTokenRequest(
+	c *fiber.Ctx,
+	req *payload.RefreshTokenRequest,
+) *authpbv1.RefreshTokenRequest {
+	refreshToken := req.RefreshToken
+	if refreshToken == "" {
+		refreshToken = h.cookies.RefreshToken(c)
+	}
 
-	return c.Status(http.StatusOK).JSON(apiResp)
+	return &authpbv1.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		IpAddress:    c.IP(),
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+	}
 }
 
-func (h *AuthHTTPHandler) signUp(c *fiber.Ctx) error {
-	var req payload.SignUpRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(
-			contract.NewErrorResponse(contract.ErrorCodeValidation, err.Error()),
-		)
+func (h *AuthHTTPHandler) toRefreshTokenResponse(
+	c *fiber.Ctx,
+	resp *authpbv1.RefreshTokenResponse,
+) *payload.RefreshTokenResponse {
+	if h.setTokenCookies(c, resp.GetAccessToken(), resp.GetRefreshToken()) {
+		return &payload.RefreshTokenResponse{}
 	}
 
-	if errs := validator.ValidateStruct(req); len(errs) != 0 {
-		return c.Status(http.StatusBadRequest).JSON(
-			contract.NewValidationErrorResponse(errs),
-		)
+	return &payload.RefreshTokenResponse{
+		AccessToken:  resp.GetAccessToken(),
+		RefreshToken: resp.GetRefreshToken(),
+	}
+}
+
+func (h *AuthHTTPHandler) toLoginTokenResponse(c *fiber.Ctx, resp *authpbv1.LoginResponse) *payload.TokenResponse {
+	return h.toTokenResponse(c, resp.GetAccessToken(), resp.GetRefreshToken())
+}
+
+func (h *AuthHTTPHandler) toSignUpTokenResponse(c *fiber.Ctx, resp *authpbv1.SignUpResponse) *payload.TokenResponse {
+	return h.toTokenResponse(c, resp.GetAccessToken(), resp.GetRefreshToken())
+}
+
+func (h *AuthHTTPHandler) toRefreshTokenTokenResponse(
+	c *fiber.Ctx,
+	resp *authpbv1.RefreshTokenResponse,
+) *payload.TokenResponse {
+	return h.toTokenResponse(c, resp.GetAccessToken(), resp.GetRefreshToken())
+}
+
+func (h *AuthHTTPHandler) toTokenResponse(c *fiber.Ctx, accessToken, refreshToken string) *payload.TokenResponse {
+	resp := &payload.TokenResponse{
+		TokenType: tokenTypeBearer,
+		ExpiresIn: max(int64(time.Until(auth.ExpiresAt(accessToken)).Seconds()), 0),
+	}
+	if !h.setTokenCookies(c, accessToken, refreshToken) {
+		resp.AccessToken = accessToken
+		resp.RefreshToken = refreshToken
 	}
 
-	grpcResp, err := h.authServiceClient.Client.SignUp(c.Context(), &authpbv1.SignUpRequest{
-		Email:    req.Email,
-		Password: req.Password,
-		FullName: req.FullName,
-	})
-	if err != nil {
-		st := status.Convert(err)
-		h.logger.Error().Err(st.Err()).Msg("Failed to sign up")
+	return resp
+}
 
-		errorCode := contract.ErrorCodeFromGRPCCode(st.Code())
-		httpStatus := contract.HTTPStatusFromGRPCCode(st.Code())
+// setTokenCookies sets the tokens as cookies when the client asked for the cookie token transport,
+// and reports whether it did, in which case the tokens must be left out of the response body.
+func (h *AuthHTTPHandler) setTokenCookies(c *fiber.Ctx, accessToken, refreshToken string) bool {
+	if !h.cookies.Requested(c) {
+		return false
+	}
+
+	h.cookies.SetTokens(c, accessToken, refreshToken, routerPrefix(h.router)+authcookie.RefreshPath)
+	return true
+}
 
-		return c.Status(httpStatus).JSON(
-			contract.NewErrorResponse(errorCode, "failed to sign up"),
-		)
+func toRevokeSuspiciousLoginRequest(
+	_ *fiber.Ctx,
+	req *payload.RevokeSuspiciousLoginRequest,
+) *authpbv1.RevokeSuspiciousLoginRequest {
+	return &authpbv1.RevokeSuspiciousLoginRequest{
+		Token: req.Token,
 	}
+}
 
-	apiResp := contract.NewSuccessResponse(&payload.SignUpResponse{
-		AccessToken:  grpcResp.GetAccessToken(),
-		RefreshToken: grpcResp.GetRefreshToken(),
-	})
+func toRequestPasswordResetRequest(
+	_ *fiber.Ctx,
+	req *payload.RequestPasswordResetRequest,
+) *authpbv1.RequestPasswordResetRequest {
+	return &authpbv1.RequestPasswordResetRequest{
+		Email: req.Email,
+	}
+}
 
-	return c.Status(http.StatusOK).JSON(apiResp)
+func toResetPasswordRequest(_ *fiber.Ctx, req *payload.ResetPasswordRequest) *authpbv1.ResetPasswordRequest {
+	return &authpbv1.ResetPasswordRequest{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}
 }
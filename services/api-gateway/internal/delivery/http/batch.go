@@ -0,0 +1,247 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/validator"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+)
+
+// batchForwardedHeaders are the headers of a batch that its sub-requests inherit, so that each of
+// them is authenticated, localized and attributed to the client like a request of its own.
+var batchForwardedHeaders = []string{
+	fiber.HeaderAuthorization,
+	fiber.HeaderCookie,
+	fiber.HeaderAcceptLanguage,
+	fiber.HeaderUserAgent,
+	fiber.HeaderXForwardedProto,
+	fiber.HeaderXForwardedHost,
+	middleware.HeaderAPIKey,
+	authcookie.HeaderCSRFToken,
+}
+
+// batchResponseHeaders are the headers of the sub-responses that are returned with their envelopes.
+var batchResponseHeaders = []string{
+	fiber.HeaderETag,
+	fiber.HeaderRetryAfter,
+	middleware.HeaderIdempotentReplayed,
+	proxy.HeaderDeprecation,
+	proxy.HeaderSunset,
+}
+
+// BatchOptions configures the batch endpoint. MaxRequests is the number of sub-requests a batch may
+// hold. ProxyHeader is the header the client IP is read from, which sub-requests inherit too.
+type BatchOptions struct {
+	MaxRequests int
+	ProxyHeader string
+}
+
+// batchOperation is the name of the batch endpoint's operation, which batches cannot nest.
+const batchOperation = "ExecuteBatch"
+
+// BatchHTTPHandler serves batches of sub-requests to the routes of the table. The sub-requests run
+// concurrently through the app, with the middleware of their routes, and do not depend on each
+// other: one failing does not affect the others, and each returns its own status and envelope.
+// The batch endpoint is expected to be served behind the auth, rate limit and body limit middleware
+// of the user's routes, which every sub-request goes through again.
+type BatchHTTPHandler struct {
+	table            *proxy.Table
+	router           fiber.Router
+	maxRequests      int
+	forwardedHeaders []string
+	// handler is the app's request handler, which is only complete once every route is registered.
+	handler func() fasthttp.RequestHandler
+}
+
+func NewBatchHTTPHandler(
+	app *fiber.App,
+	table *proxy.Table,
+	router fiber.Router,
+	options BatchOptions,
+) *BatchHTTPHandler {
+	forwardedHeaders := slices.Clone(batchForwardedHeaders)
+	if options.ProxyHeader != "" {
+		forwardedHeaders = append(forwardedHeaders, options.ProxyHeader)
+	}
+
+	return &BatchHTTPHandler{
+		table:            table,
+		router:           router,
+		maxRequests:      options.MaxRequests,
+		forwardedHeaders: forwardedHeaders,
+		handler:          sync.OnceValue(app.Handler),
+	}
+}
+
+// RegisterRoutes registers the batch endpoint in the table of the routes it serves.
+func (h *BatchHTTPHandler) RegisterRoutes() {
+	h.table.RegisterAuthenticated(h.router,
+		proxy.NewHandler[payload.BatchRequest, []payload.BatchResponse](
+			fiber.MethodPost,
+			"/batch",
+			batchOperation,
+			h.batch,
+		),
+	)
+}
+
+// batchContext holds what the sub-requests of a batch take from it. It is read from the batch
+// request before the sub-requests start, since the request must not be used concurrently.
+type batchContext struct {
+	headers    map[string]string
+	host       string
+	remoteAddr net.Addr
+	requestID  string
+	locale     string
+}
+
+func (h *BatchHTTPHandler) batch(c *fiber.Ctx) error {
+	var req payload.BatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.JSON(
+			c,
+			http.StatusBadRequest,
+			contract.NewErrorResponse(contract.ErrorCodeValidation, err.Error()),
+		)
+	}
+	if errs := validator.ValidateStruct(req, i18n.Locale(c)); len(errs) != 0 {
+		return response.JSON(c, http.StatusBadRequest, contract.NewValidationErrorResponse(errs))
+	}
+	if len(req.Requests) > h.maxRequests {
+		return response.JSON(
+			c,
+			http.StatusBadRequest,
+			contract.NewErrorResponse(contract.ErrorCodeBadRequest, "batch has too many requests"),
+		)
+	}
+	if !uniqueIDs(req.Requests) {
+		return response.JSON(
+			c,
+			http.StatusBadRequest,
+			contract.NewErrorResponse(contract.ErrorCodeBadRequest, "batch request IDs must be unique"),
+		)
+	}
+
+	batch := &batchContext{
+		headers:    make(map[string]string),
+		host:       strings.Clone(string(c.Request().Host())),
+		remoteAddr: c.Context().RemoteAddr(),
+		requestID:  requestid.FromContext(c.UserContext()),
+		locale:     i18n.Locale(c),
+	}
+	for _, name := range h.forwardedHeaders {
+		if value := c.Get(name); value != "" {
+			batch.headers[name] = strings.Clone(value)
+		}
+	}
+
+	responses := make([]payload.BatchResponse, len(req.Requests))
+	var wg sync.WaitGroup
+	for i, sub := range req.Requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = h.run(batch, i, sub)
+		}()
+	}
+	wg.Wait()
+
+	return response.JSON(c, http.StatusOK, contract.NewSuccessResponse(responses))
+}
+
+// run serves a sub-request through the app and returns its response. Sub-requests to routes that
+// are not in the table, that do not return the envelope of an authenticated route, or that are
+// batches themselves, are refused without being served.
+func (h *BatchHTTPHandler) run(batch *batchContext, index int, sub payload.BatchSubRequest) payload.BatchResponse {
+	op, ok := h.table.Lookup(sub.Method, sub.Path)
+	switch {
+	case !ok:
+		return batch.errorResponse(sub.ID, http.StatusNotFound, contract.ErrorCodeNotFound, "route not found")
+	case !op.Authenticated || op.ContentType != "" || op.Name == batchOperation:
+		return batch.errorResponse(
+			sub.ID,
+			http.StatusBadRequest,
+			contract.ErrorCodeBadRequest,
+			"route cannot be called in a batch",
+		)
+	}
+
+	var req fasthttp.Request
+	req.Header.SetMethod(sub.Method)
+	req.SetRequestURI(sub.Path)
+	req.Header.SetHost(batch.host)
+	for name, value := range batch.headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range sub.Headers {
+		req.Header.Set(name, value)
+	}
+	if batch.requestID != "" {
+		req.Header.Set(requestid.Header, batch.requestID+":"+strconv.Itoa(index))
+	}
+	if len(sub.Body) > 0 {
+		req.Header.SetContentType(fiber.MIMEApplicationJSON)
+		req.SetBody(sub.Body)
+	}
+
+	var ctx fasthttp.RequestCtx
+	ctx.Init(&req, batch.remoteAddr, nil)
+	h.handler()(&ctx)
+
+	resp := payload.BatchResponse{ID: sub.ID, Status: ctx.Response.StatusCode()}
+	for _, name := range batchResponseHeaders {
+		if value := ctx.Response.Header.Peek(name); len(value) > 0 {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]string)
+			}
+			resp.Headers[name] = string(value)
+		}
+	}
+	if body := ctx.Response.Body(); json.Valid(body) {
+		resp.Body = bytes.Clone(body)
+	}
+
+	return resp
+}
+
+// errorResponse returns the response of a sub-request that was refused, with the error envelope
+// the route would have returned.
+func (b *batchContext) errorResponse(id string, status int, code, message string) payload.BatchResponse {
+	envelope := contract.NewErrorResponse(code, i18n.Translate(b.locale, message))
+	envelope.RequestID = b.requestID
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return payload.BatchResponse{ID: id, Status: status}
+	}
+
+	return payload.BatchResponse{ID: id, Status: status, Body: body}
+}
+
+func uniqueIDs(requests []payload.BatchSubRequest) bool {
+	seen := make(map[string]struct{}, len(requests))
+	for _, sub := range requests {
+		if _, ok := seen[sub.ID]; ok {
+			return false
+		}
+		seen[sub.ID] = struct{}{}
+	}
+
+	return true
+}
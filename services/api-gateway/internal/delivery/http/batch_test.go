@@ -0,0 +1,185 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	httphandler "github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/delivery/http"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type echoRequest struct {
+	Message string `query:"message"`
+}
+
+func echo(_ context.Context, in *wrapperspb.StringValue, _ ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	if in.GetValue() == "fail" {
+		return nil, contract.NewGRPCError(codes.NotFound, contract.ErrorCodeNotFound, "nothing to echo")
+	}
+
+	return wrapperspb.String(strings.ToUpper(in.GetValue())), nil
+}
+
+func toEchoRequest(_ *fiber.Ctx, req *echoRequest) *wrapperspb.StringValue {
+	return wrapperspb.String(req.Message)
+}
+
+func toEchoResponse(_ *fiber.Ctx, out *wrapperspb.StringValue) string {
+	return out.GetValue()
+}
+
+func newBatchApp() *fiber.App {
+	app := fiber.New()
+	table := proxy.NewTable()
+
+	auth := func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) != "Bearer token" {
+			return response.JSON(
+				c,
+				http.StatusUnauthorized,
+				contract.NewErrorResponse(contract.ErrorCodeUnauthorized, "missing access token"),
+			)
+		}
+		return c.Next()
+	}
+
+	table.RegisterAuthenticated(app.Group("/v1/me", auth),
+		proxy.NewRoute(fiber.MethodGet, "/echo", echo, toEchoRequest, toEchoResponse),
+	)
+	table.Register(app.Group("/v1/public"),
+		proxy.NewRoute(fiber.MethodGet, "/echo", echo, toEchoRequest, toEchoResponse),
+	)
+	httphandler.NewBatchHTTPHandler(app, table, app.Group("/v1"), httphandler.BatchOptions{MaxRequests: 4}).
+		RegisterRoutes()
+
+	return app
+}
+
+func doBatch(t *testing.T, app *fiber.App, authorization, body string) (int, []payload.BatchResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/batch", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data []payload.BatchResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	return resp.StatusCode, envelope.Data
+}
+
+func subResponse(t *testing.T, resp payload.BatchResponse) contract.APIResponse {
+	t.Helper()
+
+	var envelope contract.APIResponse
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		t.Fatalf("decode body of %s: %v", resp.ID, err)
+	}
+
+	return envelope
+}
+
+func TestBatch(t *testing.T) {
+	app := newBatchApp()
+
+	t.Run("serves every sub-request on its own", func(t *testing.T) {
+		status, responses := doBatch(t, app, "Bearer token", `{"requests": [
+			{"id": "hi", "method": "GET", "path": "/v1/me/echo?message=hi"},
+			{"id": "fail", "method": "GET", "path": "/v1/me/echo?message=fail"},
+			{"id": "unknown", "method": "GET", "path": "/v1/nothing"},
+			{"id": "public", "method": "GET", "path": "/v1/public/echo?message=hi"}
+		]}`)
+		if status != http.StatusOK || len(responses) != 4 {
+			t.Fatalf("status is %d with %d responses, want %d with 4", status, len(responses), http.StatusOK)
+		}
+
+		want := []struct {
+			id     string
+			status int
+		}{
+			{id: "hi", status: http.StatusOK},
+			{id: "fail", status: http.StatusNotFound},
+			{id: "unknown", status: http.StatusNotFound},
+			{id: "public", status: http.StatusBadRequest},
+		}
+		for i, w := range want {
+			if responses[i].ID != w.id || responses[i].Status != w.status {
+				t.Errorf("response %d is %s %d, want %s %d", i, responses[i].ID, responses[i].Status, w.id, w.status)
+			}
+		}
+		if data := subResponse(t, responses[0]).Data; data != "HI" {
+			t.Errorf("data of hi is %v, want %q", data, "HI")
+		}
+		if err := subResponse(t, responses[1]).Error; err == nil || err.Message != "nothing to echo" {
+			t.Errorf("error of fail is %+v, want the service error", err)
+		}
+	})
+
+	t.Run("authenticates each sub-request", func(t *testing.T) {
+		_, responses := doBatch(t, app, "", `{"requests": [
+			{"id": "hi", "method": "GET", "path": "/v1/me/echo?message=hi"}
+		]}`)
+		if len(responses) != 1 || responses[0].Status != http.StatusUnauthorized {
+			t.Fatalf("responses are %+v, want one with status %d", responses, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("refuses nested batches", func(t *testing.T) {
+		_, responses := doBatch(t, app, "Bearer token", `{"requests": [
+			{"id": "batch", "method": "POST", "path": "/v1/batch", "body": {"requests": [
+				{"id": "hi", "method": "GET", "path": "/v1/me/echo?message=hi"}
+			]}}
+		]}`)
+		if len(responses) != 1 || responses[0].Status != http.StatusBadRequest {
+			t.Fatalf("responses are %+v, want one with status %d", responses, http.StatusBadRequest)
+		}
+		if err := subResponse(t, responses[0]).Error; err == nil || err.Message != "route cannot be called in a batch" {
+			t.Errorf("error of batch is %+v, want the batch to be refused", err)
+		}
+	})
+
+	t.Run("rejects invalid batches", func(t *testing.T) {
+		for _, body := range []string{
+			`{"requests": []}`,
+			`{"requests": [{"id": "a", "method": "TRACE", "path": "/v1/me/echo"}]}`,
+			`{"requests": [
+				{"id": "a", "method": "GET", "path": "/v1/me/echo"},
+				{"id": "a", "method": "GET", "path": "/v1/me/echo"}
+			]}`,
+			`{"requests": [
+				{"id": "a", "method": "GET", "path": "/v1/me/echo"},
+				{"id": "b", "method": "GET", "path": "/v1/me/echo"},
+				{"id": "c", "method": "GET", "path": "/v1/me/echo"},
+				{"id": "d", "method": "GET", "path": "/v1/me/echo"},
+				{"id": "e", "method": "GET", "path": "/v1/me/echo"}
+			]}`,
+		} {
+			if status, _ := doBatch(t, app, "Bearer token", body); status != http.StatusBadRequest {
+				t.Errorf("status of %s is %d, want %d", body, status, http.StatusBadRequest)
+			}
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/realtime"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/validator"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+)
+
+const (
+	eventTypeSecurityEvent = "security_event"
+	eventTypeHeartbeat     = "heartbeat"
+
+	eventStreamContentType = "text/event-stream"
+
+	transportWebSocket = "websocket"
+	transportSSE       = "sse"
+
+	// eventWriteTimeout bounds writing an event to a client that stopped reading.
+	eventWriteTimeout = 10 * time.Second
+	// sseRetry is how long EventSource clients wait before reconnecting.
+	sseRetry = 3 * time.Second
+	// closeTokenExpired is the WebSocket close code sent when the access token expires. Clients
+	// refresh their tokens and reconnect with the ID of the last event they received.
+	closeTokenExpired = 4001
+)
+
+// EventsHTTPHandler streams the authenticated user's events over WebSocket, or over Server-Sent
+// Events for clients that do not upgrade the connection. Its router is expected to be a "/me"
+// group with the auth middleware already applied.
+type EventsHTTPHandler struct {
+	hub    *realtime.Hub
+	router fiber.Router
+}
+
+func NewEventsHTTPHandler(hub *realtime.Hub, router fiber.Router) *EventsHTTPHandler {
+	return &EventsHTTPHandler{
+		hub:    hub,
+		router: router,
+	}
+}
+
+// RegisterRoutes registers the event stream. It is documented as Server-Sent Events, whose events
+// carry the same payloads as the WebSocket messages.
+func (h *EventsHTTPHandler) RegisterRoutes(table *proxy.Table) {
+	table.RegisterAuthenticated(h.router,
+		proxy.NewHandler[payload.SubscribeEventsRequest, payload.EventResponse](
+			fiber.MethodGet,
+			"/events",
+			"SubscribeEvents",
+			h.subscribe,
+		).WithContentType(eventStreamContentType),
+	)
+}
+
+// eventStream is a client's subscription. It is read from the request before the handler returns,
+// since the connection is served after the request context has been released.
+type eventStream struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	userID      string
+	lastEventID string
+	release     func()
+}
+
+func (h *EventsHTTPHandler) subscribe(c *fiber.Ctx) error {
+	var req payload.SubscribeEventsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.JSON(
+			c,
+			http.StatusBadRequest,
+			contract.NewErrorResponse(contract.ErrorCodeValidation, err.Error()),
+		)
+	}
+	if req.LastEventID == "" {
+		req.LastEventID = c.Get(middleware.HeaderLastEventID)
+	}
+	if errs := validator.ValidateStruct(req, i18n.Locale(c)); len(errs) != 0 {
+		return response.JSON(c, http.StatusBadRequest, contract.NewValidationErrorResponse(errs))
+	}
+
+	userID := middleware.UserID(c)
+	release, err := h.hub.Connect(userID)
+	if err != nil {
+		return response.JSON(
+			c,
+			http.StatusTooManyRequests,
+			contract.NewErrorResponse(contract.ErrorCodeRateLimit, "too many open event streams"),
+		)
+	}
+
+	// The stream ends when the access token expires, so that it is not served past the session.
+	parent := context.WithoutCancel(c.UserContext())
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if expiresAt := middleware.TokenExpiresAt(c); !expiresAt.IsZero() {
+		ctx, cancel = context.WithDeadline(parent, expiresAt)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+
+	stream := &eventStream{
+		ctx:         ctx,
+		cancel:      cancel,
+		userID:      userID,
+		lastEventID: strings.Clone(req.LastEventID),
+		release:     release,
+	}
+
+	if websocket.IsWebSocketUpgrade(c) {
+		if err := websocket.New(func(conn *websocket.Conn) { h.serveWebSocket(conn, stream) })(c); err != nil {
+			stream.close()
+			return err
+		}
+		return nil
+	}
+
+	h.serveSSE(c, stream)
+
+	return nil
+}
+
+func (h *EventsHTTPHandler) serveWebSocket(conn *websocket.Conn, stream *eventStream) {
+	defer stream.close()
+	metrics.RealtimeConnectionOpened(transportWebSocket)
+	defer metrics.RealtimeConnectionClosed(transportWebSocket)
+
+	// Clients send nothing but control frames; reading detects when they close the connection.
+	go func() {
+		defer stream.cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	code, reason := websocket.CloseGoingAway, ""
+	err := h.hub.Stream(stream.ctx, stream.userID, stream.lastEventID, &webSocketSink{conn: conn})
+	switch {
+	case err != nil:
+		logger.FromContext(stream.ctx).Warn().Err(err).Msg("Failed to stream events")
+		code = websocket.CloseInternalServerErr
+	case errors.Is(stream.ctx.Err(), context.DeadlineExceeded):
+		code, reason = closeTokenExpired, "access token expired"
+	}
+
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(eventWriteTimeout))
+}
+
+func (h *EventsHTTPHandler) serveSSE(c *fiber.Ctx, stream *eventStream) {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// Proxies must pass events on as they are written.
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer stream.close()
+		metrics.RealtimeConnectionOpened(transportSSE)
+		defer metrics.RealtimeConnectionClosed(transportSSE)
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		if err := h.hub.Stream(stream.ctx, stream.userID, stream.lastEventID, &sseSink{w: w}); err != nil {
+			logger.FromContext(stream.ctx).Debug().Err(err).Msg("Event stream closed")
+		}
+	})
+}
+
+func (s *eventStream) close() {
+	s.cancel()
+	s.release()
+}
+
+// webSocketSink writes events to a WebSocket connection as JSON messages.
+type webSocketSink struct {
+	conn *websocket.Conn
+}
+
+func (s *webSocketSink) Send(event *authpbv1.Event) error {
+	resp, ok := toEventResponse(event)
+	if !ok {
+		return nil
+	}
+
+	return s.write(resp)
+}
+
+func (s *webSocketSink) Heartbeat() error {
+	return s.write(payload.EventResponse{Type: eventTypeHeartbeat})
+}
+
+func (s *webSocketSink) write(resp payload.EventResponse) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout)); err != nil {
+		return err
+	}
+
+	return s.conn.WriteJSON(resp)
+}
+
+// sseSink writes events to a Server-Sent Events stream, with the event's data as JSON.
+type sseSink struct {
+	w *bufio.Writer
+}
+
+func (s *sseSink) Send(event *authpbv1.Event) error {
+	resp, ok := toEventResponse(event)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "id: %s\nevent: %s\ndata: %s\n\n", resp.ID, resp.Type, data); err != nil {
+		return err
+	}
+
+	return s.w.Flush()
+}
+
+func (s *sseSink) Heartbeat() error {
+	// Comments keep the connection open without dispatching an event.
+	if _, err := s.w.WriteString(": " + eventTypeHeartbeat + "\n\n"); err != nil {
+		return err
+	}
+
+	return s.w.Flush()
+}
+
+// toEventResponse returns the client message of the event, or false for kinds of events this
+// version of the gateway does not know.
+func toEventResponse(event *authpbv1.Event) (payload.EventResponse, bool) {
+	switch p := event.GetPayload().(type) {
+	case *authpbv1.Event_SecurityEvent:
+		return payload.EventResponse{
+			ID:   event.GetId(),
+			Type: eventTypeSecurityEvent,
+			Data: toSecurityEventResponse(p.SecurityEvent),
+		}, true
+	default:
+		return payload.EventResponse{}, false
+	}
+}
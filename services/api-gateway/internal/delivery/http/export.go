@@ -0,0 +1,110 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+)
+
+const exportStatusCompleted = "completed"
+
+// ExportHTTPHandler serves the user data export routes. Its router is expected to be
+// a "/me" group with the auth middleware already applied.
+type ExportHTTPHandler struct {
+	authServiceClient *authclient.AuthServiceClient
+	router            fiber.Router
+}
+
+func NewExportHTTPHandler(
+	authServiceClient *authclient.AuthServiceClient,
+	router fiber.Router,
+) *ExportHTTPHandler {
+	return &ExportHTTPHandler{
+		authServiceClient: authServiceClient,
+		router:            router,
+	}
+}
+
+func (h *ExportHTTPHandler) RegisterRoutes(table *proxy.Table) {
+	client := h.authServiceClient.Client
+
+	table.RegisterAuthenticated(h.router.Group("/exports"),
+		proxy.NewRoute(
+			fiber.MethodPost,
+			"/",
+			client.ExportUserData,
+			toExportUserDataRequest,
+			h.toExportUserDataResponse,
+		).WithStatus(http.StatusAccepted),
+		proxy.NewRoute(fiber.MethodGet, "/:id", client.GetExportJob, toGetExportJobRequest, h.toGetExportJobResponse).
+			WithCache(proxy.Cache{}),
+		proxy.NewCommand(fiber.MethodGet, "/:id/download", client.DownloadExport, toDownloadExportRequest).
+			WithWriter(fiber.MIMEApplicationJSON, writeExportArchive),
+	)
+}
+
+func toExportUserDataRequest(c *fiber.Ctx, _ *proxy.Empty) *authpbv1.ExportUserDataRequest {
+	return &authpbv1.ExportUserDataRequest{
+		UserId: middleware.UserID(c),
+	}
+}
+
+func (h *ExportHTTPHandler) toExportUserDataResponse(
+	c *fiber.Ctx,
+	resp *authpbv1.ExportUserDataResponse,
+) *payload.ExportJobResponse {
+	return h.toExportJobResponse(c, resp.GetJob())
+}
+
+func toGetExportJobRequest(c *fiber.Ctx, req *payload.ExportJobRequest) *authpbv1.GetExportJobRequest {
+	return &authpbv1.GetExportJobRequest{
+		UserId: middleware.UserID(c),
+		JobId:  req.ID,
+	}
+}
+
+func (h *ExportHTTPHandler) toGetExportJobResponse(
+	c *fiber.Ctx,
+	resp *authpbv1.GetExportJobResponse,
+) *payload.ExportJobResponse {
+	return h.toExportJobResponse(c, resp.GetJob())
+}
+
+func toDownloadExportRequest(c *fiber.Ctx, req *payload.ExportJobRequest) *authpbv1.DownloadExportRequest {
+	return &authpbv1.DownloadExportRequest{
+		UserId: middleware.UserID(c),
+		JobId:  req.ID,
+	}
+}
+
+func writeExportArchive(c *fiber.Ctx, resp *authpbv1.DownloadExportResponse) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", resp.GetFileName()))
+
+	return c.Status(http.StatusOK).Send(resp.GetArchive())
+}
+
+func (h *ExportHTTPHandler) toExportJobResponse(c *fiber.Ctx, job *authpbv1.ExportJob) *payload.ExportJobResponse {
+	resp := &payload.ExportJobResponse{
+		ID:        job.GetId(),
+		Status:    job.GetStatus(),
+		Error:     job.GetError(),
+		CreatedAt: job.GetCreatedAt().AsTime(),
+	}
+
+	if job.GetStatus() == exportStatusCompleted {
+		resp.DownloadURL = c.BaseURL() + routerPrefix(h.router) + "/exports/" + job.GetId() + "/download"
+	}
+	if job.GetCompletedAt() != nil {
+		completedAt := job.GetCompletedAt().AsTime()
+		resp.CompletedAt = &completedAt
+	}
+
+	return resp
+}
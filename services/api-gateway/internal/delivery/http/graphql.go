@@ -0,0 +1,413 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graph-gophers/dataloader/v7"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+)
+
+const (
+	// graphQLLoaderWait is how long the loaders of a GraphQL request wait for more keys before
+	// running a batch.
+	graphQLLoaderWait = time.Millisecond
+	// graphQLOverlapLimit caps the pairs of fields that validation compares to merge selections.
+	// Documents that spread the same fragments over and over exceed it and are rejected before
+	// anything is loaded.
+	graphQLOverlapLimit = 1000
+	// maxSecurityEventsPage bounds the security events of a page, like the limit of
+	// GET /me/security-events. The schema defaults pages to 20 events.
+	maxSecurityEventsPage = 100
+)
+
+// graphQLSchema is the schema of the GraphQL API. Its resolvers are the graphQL*Resolver types below.
+const graphQLSchema = `
+scalar Time
+
+type Query {
+	me: User!
+}
+
+type User {
+	id: ID!
+	email: String!
+	fullName: String!
+	displayName: String!
+	avatarUrl: String!
+	locale: String!
+	timezone: String!
+	defaultCurrency: String!
+	verified: Boolean!
+	createdAt: Time!
+	updatedAt: Time!
+	# securityEvents is nullable, so that the user is still returned when they fail to load.
+	securityEvents(first: Int = 20, after: String): SecurityEventConnection
+}
+
+type SecurityEventConnection {
+	nodes: [SecurityEvent!]!
+	pageInfo: PageInfo!
+}
+
+type SecurityEvent {
+	id: ID!
+	type: String!
+	sessionId: ID
+	ipAddress: String
+	userAgent: String
+	reason: String
+	createdAt: Time!
+}
+
+type PageInfo {
+	nextCursor: String
+	hasMore: Boolean!
+}
+`
+
+// GraphQLOptions limits how deeply GraphQL queries nest and how many list items they load.
+type GraphQLOptions struct {
+	MaxDepth int
+	MaxItems int
+}
+
+// GraphQLHTTPHandler serves read-only GraphQL queries over the backend gRPC services. Its router is
+// expected to have the auth middleware already applied: every query reads the signed-in user's
+// resources. The resolvers call the gRPC clients through the loaders of the request, so that a
+// resource selected more than once is loaded once.
+type GraphQLHTTPHandler struct {
+	authServiceClient *authclient.AuthServiceClient
+	router            fiber.Router
+	schema            *graphql.Schema
+	maxItems          int
+}
+
+func NewGraphQLHTTPHandler(
+	authServiceClient *authclient.AuthServiceClient,
+	router fiber.Router,
+	options GraphQLOptions,
+) *GraphQLHTTPHandler {
+	return &GraphQLHTTPHandler{
+		authServiceClient: authServiceClient,
+		router:            router,
+		schema: graphql.MustParseSchema(
+			graphQLSchema,
+			&graphQLQueryResolver{},
+			graphql.MaxDepth(options.MaxDepth),
+			graphql.OverlapValidationLimit(graphQLOverlapLimit),
+			graphql.DisableIntrospection(),
+			graphql.Logger(graphQLPanicLogger{}),
+		),
+		maxItems: options.MaxItems,
+	}
+}
+
+// RegisterRoutes registers the GraphQL endpoint. Its responses are GraphQL responses rather than the
+// response envelope.
+func (h *GraphQLHTTPHandler) RegisterRoutes(table *proxy.Table) {
+	table.RegisterAuthenticated(h.router,
+		proxy.NewHandler[payload.GraphQLRequest, proxy.Empty](fiber.MethodPost, "/", "QueryGraphQL", h.query).
+			WithSummary("Query the GraphQL API").
+			WithContentType(fiber.MIMEApplicationJSON),
+	)
+}
+
+func (h *GraphQLHTTPHandler) query(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req payload.GraphQLRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(graphql.Response{
+			Errors: []*gqlerrors.QueryError{{Message: err.Error()}},
+		})
+	}
+	if req.Query == "" {
+		return c.Status(http.StatusBadRequest).JSON(graphql.Response{
+			Errors: []*gqlerrors.QueryError{{Message: i18n.Translate(i18n.Locale(c), "query is required")}},
+		})
+	}
+
+	ctx := context.WithValue(c.UserContext(), graphQLRequestKey{}, h.newRequest(c))
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	// Documents that fail to parse or validate are not executed and have no data.
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(resp)
+}
+
+type graphQLRequestKey struct{}
+
+// graphQLRequest is the state of a GraphQL request that its resolvers read from the context: the
+// signed-in user, the locale of error messages, the loaders, which live for the request only, and
+// the number of list items loaded so far.
+type graphQLRequest struct {
+	userID         string
+	locale         string
+	maxItems       int
+	items          atomic.Int64
+	users          *dataloader.Loader[string, *authpbv1.User]
+	securityEvents *dataloader.Loader[securityEventsKey, *authpbv1.ListSecurityEventsResponse]
+}
+
+type securityEventsKey struct {
+	userID string
+	first  int
+	after  string
+}
+
+func (h *GraphQLHTTPHandler) newRequest(c *fiber.Ctx) *graphQLRequest {
+	client := h.authServiceClient.Client
+	r := &graphQLRequest{
+		userID:   middleware.UserID(c),
+		locale:   i18n.Locale(c),
+		maxItems: h.maxItems,
+	}
+
+	r.users = dataloader.NewBatchedLoader(
+		perKey(func(ctx context.Context, userID string) (*authpbv1.User, error) {
+			resp, err := client.GetMe(ctx, &authpbv1.GetMeRequest{UserId: userID})
+			if err != nil {
+				return nil, r.error(ctx, "GetMe", err)
+			}
+			return resp.GetUser(), nil
+		}),
+		dataloader.WithWait[string, *authpbv1.User](graphQLLoaderWait),
+	)
+	r.securityEvents = dataloader.NewBatchedLoader(
+		perKey(func(ctx context.Context, key securityEventsKey) (*authpbv1.ListSecurityEventsResponse, error) {
+			if err := r.load(key.first); err != nil {
+				return nil, err
+			}
+
+			resp, err := client.ListSecurityEvents(ctx, &authpbv1.ListSecurityEventsRequest{
+				UserId: key.userID,
+				Limit:  uint64(key.first),
+				Cursor: key.after,
+			})
+			if err != nil {
+				return nil, r.error(ctx, "ListSecurityEvents", err)
+			}
+			return resp, nil
+		}),
+		dataloader.WithWait[securityEventsKey, *authpbv1.ListSecurityEventsResponse](graphQLLoaderWait),
+	)
+
+	return r
+}
+
+// load counts a page of n items against the items the request may load. Pages selected more than
+// once are loaded, and counted, once.
+func (r *graphQLRequest) load(n int) error {
+	if r.maxItems > 0 && r.items.Add(int64(n)) > int64(r.maxItems) {
+		return &graphQLError{
+			message: i18n.Translate(r.locale, "query loads too many items"),
+			code:    contract.ErrorCodeValidation,
+		}
+	}
+
+	return nil
+}
+
+// error logs a failed gRPC call and translates its error like the REST routes do, with the error
+// code in the extensions of the GraphQL error.
+func (r *graphQLRequest) error(ctx context.Context, method string, err error) error {
+	logger.FromContext(ctx).Error().
+		Err(err).
+		Str("method", method).
+		Msg("Failed to call gRPC method")
+
+	resp := contract.NewGRPCErrorResponse(err).Response.Error
+	return &graphQLError{message: i18n.Translate(r.locale, resp.Message), code: resp.Code}
+}
+
+func graphQLRequestFrom(ctx context.Context) *graphQLRequest {
+	return ctx.Value(graphQLRequestKey{}).(*graphQLRequest)
+}
+
+// perKey returns a batch function that loads the keys of a batch one by one, concurrently, for
+// backends that have no batch method yet.
+func perKey[K comparable, V any](
+	load func(ctx context.Context, key K) (V, error),
+) dataloader.BatchFunc[K, V] {
+	return func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		results := make([]*dataloader.Result[V], len(keys))
+		done := make(chan struct{}, len(keys))
+		for i, key := range keys {
+			go func() {
+				value, err := load(ctx, key)
+				results[i] = &dataloader.Result[V]{Data: value, Error: err}
+				done <- struct{}{}
+			}()
+		}
+		for range keys {
+			<-done
+		}
+
+		return results
+	}
+}
+
+// graphQLError is a resolver error with the error code of the REST API in its extensions.
+type graphQLError struct {
+	message string
+	code    string
+}
+
+func (e *graphQLError) Error() string {
+	return e.message
+}
+
+func (e *graphQLError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// graphQLPanicLogger logs the panics of resolvers with the request's logger.
+type graphQLPanicLogger struct{}
+
+func (graphQLPanicLogger) LogPanic(ctx context.Context, value any) {
+	logger.FromContext(ctx).Error().
+		Interface("panic", value).
+		Msg("GraphQL resolver panicked")
+}
+
+type graphQLQueryResolver struct{}
+
+func (*graphQLQueryResolver) Me(ctx context.Context) (*graphQLUserResolver, error) {
+	r := graphQLRequestFrom(ctx)
+	user, err := r.users.Load(ctx, r.userID)()
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphQLUserResolver{user: user}, nil
+}
+
+type graphQLUserResolver struct {
+	user *authpbv1.User
+}
+
+func (u *graphQLUserResolver) ID() graphql.ID          { return graphql.ID(u.user.GetId()) }
+func (u *graphQLUserResolver) Email() string           { return u.user.GetEmail() }
+func (u *graphQLUserResolver) FullName() string        { return u.user.GetFullName() }
+func (u *graphQLUserResolver) DisplayName() string     { return u.user.GetDisplayName() }
+func (u *graphQLUserResolver) AvatarURL() string       { return u.user.GetAvatarUrl() }
+func (u *graphQLUserResolver) Locale() string          { return u.user.GetLocale() }
+func (u *graphQLUserResolver) Timezone() string        { return u.user.GetTimezone() }
+func (u *graphQLUserResolver) DefaultCurrency() string { return u.user.GetDefaultCurrency() }
+func (u *graphQLUserResolver) Verified() bool          { return u.user.GetVerified() }
+
+func (u *graphQLUserResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: u.user.GetCreatedAt().AsTime()}
+}
+
+func (u *graphQLUserResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: u.user.GetUpdatedAt().AsTime()}
+}
+
+func (u *graphQLUserResolver) SecurityEvents(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*graphQLSecurityEventConnectionResolver, error) {
+	r := graphQLRequestFrom(ctx)
+
+	first := int(args.First)
+	if first < 1 || first > maxSecurityEventsPage {
+		return nil, &graphQLError{
+			message: i18n.Translate(r.locale, "first must be between 1 and 100"),
+			code:    contract.ErrorCodeValidation,
+		}
+	}
+
+	var after string
+	if args.After != nil {
+		after = *args.After
+	}
+
+	resp, err := r.securityEvents.Load(ctx, securityEventsKey{
+		userID: u.user.GetId(),
+		first:  first,
+		after:  after,
+	})()
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphQLSecurityEventConnectionResolver{resp: resp}, nil
+}
+
+type graphQLSecurityEventConnectionResolver struct {
+	resp *authpbv1.ListSecurityEventsResponse
+}
+
+func (c *graphQLSecurityEventConnectionResolver) Nodes() []*graphQLSecurityEventResolver {
+	nodes := make([]*graphQLSecurityEventResolver, len(c.resp.GetEvents()))
+	for i, event := range c.resp.GetEvents() {
+		nodes[i] = &graphQLSecurityEventResolver{event: event}
+	}
+
+	return nodes
+}
+
+func (c *graphQLSecurityEventConnectionResolver) PageInfo() *graphQLPageInfoResolver {
+	return &graphQLPageInfoResolver{pageInfo: toSecurityEventsPageInfo(c.resp)}
+}
+
+type graphQLSecurityEventResolver struct {
+	event *authpbv1.SecurityEvent
+}
+
+func (e *graphQLSecurityEventResolver) ID() graphql.ID { return graphql.ID(e.event.GetId()) }
+func (e *graphQLSecurityEventResolver) Type() string   { return e.event.GetType() }
+func (e *graphQLSecurityEventResolver) IPAddress() *string {
+	return optionalString(e.event.GetIpAddress())
+}
+func (e *graphQLSecurityEventResolver) UserAgent() *string {
+	return optionalString(e.event.GetUserAgent())
+}
+func (e *graphQLSecurityEventResolver) Reason() *string { return optionalString(e.event.GetReason()) }
+
+func (e *graphQLSecurityEventResolver) SessionID() *graphql.ID {
+	if e.event.GetSessionId() == "" {
+		return nil
+	}
+
+	id := graphql.ID(e.event.GetSessionId())
+	return &id
+}
+
+func (e *graphQLSecurityEventResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: e.event.GetCreatedAt().AsTime()}
+}
+
+type graphQLPageInfoResolver struct {
+	pageInfo contract.PageInfo
+}
+
+func (p *graphQLPageInfoResolver) NextCursor() *string { return optionalString(p.pageInfo.NextCursor) }
+func (p *graphQLPageInfoResolver) HasMore() bool       { return p.pageInfo.HasMore }
+
+// optionalString returns nil for empty strings, which are unset in gRPC messages.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
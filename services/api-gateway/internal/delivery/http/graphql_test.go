@@ -0,0 +1,234 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	httphandler "github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/delivery/http"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeAuthClient serves GetMe and ListSecurityEvents and counts their calls.
+type fakeAuthClient struct {
+	authpbv1.AuthServiceClient
+
+	getMeCalls  atomic.Int32
+	eventsCalls atomic.Int32
+}
+
+func (f *fakeAuthClient) GetMe(
+	_ context.Context,
+	in *authpbv1.GetMeRequest,
+	_ ...grpc.CallOption,
+) (*authpbv1.GetMeResponse, error) {
+	f.getMeCalls.Add(1)
+	return &authpbv1.GetMeResponse{User: &authpbv1.User{
+		Id:        in.GetUserId(),
+		Email:     "ada@example.com",
+		FullName:  "Ada Lovelace",
+		CreatedAt: timestamppb.New(time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)),
+	}}, nil
+}
+
+func (f *fakeAuthClient) ListSecurityEvents(
+	_ context.Context,
+	in *authpbv1.ListSecurityEventsRequest,
+	_ ...grpc.CallOption,
+) (*authpbv1.ListSecurityEventsResponse, error) {
+	f.eventsCalls.Add(1)
+	if in.GetCursor() == "bad" {
+		return nil, contract.NewGRPCError(codes.InvalidArgument, contract.ErrorCodeInvalidCursor, "invalid cursor")
+	}
+
+	return &authpbv1.ListSecurityEventsResponse{
+		Events: []*authpbv1.SecurityEvent{
+			{Id: "e1", Type: "login", SessionId: "s1"},
+		},
+		NextCursor: "next",
+		HasMore:    in.GetLimit() == 1,
+	}, nil
+}
+
+func newGraphQLApp(client authpbv1.AuthServiceClient) *fiber.App {
+	app := fiber.New()
+	auth := func(c *fiber.Ctx) error {
+		c.Locals(middleware.UserIDKey, "u1")
+		return c.Next()
+	}
+
+	router := app.Group("/v1/graphql", auth)
+	httphandler.NewGraphQLHTTPHandler(
+		&authclient.AuthServiceClient{Client: client},
+		router,
+		httphandler.GraphQLOptions{MaxDepth: 4, MaxItems: 150},
+	).RegisterRoutes(proxy.NewTable())
+
+	return app
+}
+
+func doGraphQL(t *testing.T, app *fiber.App, body string) (int, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "en")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	return resp.StatusCode, result
+}
+
+func TestGraphQL(t *testing.T) {
+	t.Run("loads each resource once", func(t *testing.T) {
+		client := &fakeAuthClient{}
+		query := `query ($first: Int) {
+			me {
+				id email createdAt
+				a: securityEvents(first: $first) { nodes { id sessionId reason } pageInfo { nextCursor hasMore } }
+			}
+			profile: me { fullName b: securityEvents(first: $first) { nodes { type } } }
+		}`
+		body, err := json.Marshal(map[string]any{"query": query, "variables": map[string]any{"first": 1}})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+
+		status, result := doGraphQL(t, newGraphQLApp(client), string(body))
+		if status != http.StatusOK || result["errors"] != nil {
+			t.Fatalf("status is %d with errors %v, want %d", status, result["errors"], http.StatusOK)
+		}
+
+		got, err := json.Marshal(result["data"])
+		if err != nil {
+			t.Fatalf("marshal data: %v", err)
+		}
+		want := `{"me":{"a":{"nodes":[{"id":"e1","reason":null,"sessionId":"s1"}],` +
+			`"pageInfo":{"hasMore":true,"nextCursor":"next"}},"createdAt":"2026-01-02T03:04:05Z",` +
+			`"email":"ada@example.com","id":"u1"},` +
+			`"profile":{"b":{"nodes":[{"type":"login"}]},"fullName":"Ada Lovelace"}}`
+		if string(got) != want {
+			t.Errorf("data is %s, want %s", got, want)
+		}
+		if client.getMeCalls.Load() != 1 || client.eventsCalls.Load() != 1 {
+			t.Errorf(
+				"GetMe was called %d times and ListSecurityEvents %d times, want once each",
+				client.getMeCalls.Load(),
+				client.eventsCalls.Load(),
+			)
+		}
+	})
+
+	t.Run("returns gRPC errors with their code", func(t *testing.T) {
+		status, result := doGraphQL(t, newGraphQLApp(&fakeAuthClient{}), `{
+			"query": "{ me { id securityEvents(after: \"bad\") { nodes { id } } } }"
+		}`)
+		if status != http.StatusOK {
+			t.Fatalf("status is %d, want %d", status, http.StatusOK)
+		}
+		if data, _ := json.Marshal(result["data"]); string(data) != `{"me":{"id":"u1","securityEvents":null}}` {
+			t.Errorf("data is %s, want the user without security events", data)
+		}
+
+		errs, _ := result["errors"].([]any)
+		if len(errs) != 1 {
+			t.Fatalf("errors are %v, want one", result["errors"])
+		}
+		gqlErr, _ := errs[0].(map[string]any)
+		extensions, _ := gqlErr["extensions"].(map[string]any)
+		if gqlErr["message"] != "invalid cursor" || extensions["code"] != contract.ErrorCodeInvalidCursor {
+			t.Errorf("error is %v, want the invalid cursor error", gqlErr)
+		}
+	})
+
+	t.Run("refuses invalid queries", func(t *testing.T) {
+		for _, query := range []string{
+			`{"query": "{ me { id "}`,
+			`{"query": "{ me { password } }"}`,
+			`{"query": ""}`,
+		} {
+			if status, _ := doGraphQL(t, newGraphQLApp(&fakeAuthClient{}), query); status != http.StatusBadRequest {
+				t.Errorf("status of %s is %d, want %d", query, status, http.StatusBadRequest)
+			}
+		}
+	})
+	t.Run("limits the items a query loads", func(t *testing.T) {
+		client := &fakeAuthClient{}
+		query := `{ me {
+			a: securityEvents(first: 100) { nodes { id } }
+			b: securityEvents(first: 100, after: "x") { nodes { id } }
+		} }`
+		body, err := json.Marshal(map[string]any{"query": query})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+
+		status, result := doGraphQL(t, newGraphQLApp(client), string(body))
+		if status != http.StatusOK {
+			t.Fatalf("status is %d, want %d", status, http.StatusOK)
+		}
+
+		errs, _ := result["errors"].([]any)
+		if len(errs) != 1 {
+			t.Fatalf("errors are %v, want one", result["errors"])
+		}
+		gqlErr, _ := errs[0].(map[string]any)
+		if extensions, _ := gqlErr["extensions"].(map[string]any); extensions["code"] != contract.ErrorCodeValidation {
+			t.Errorf("error is %v, want a validation error", gqlErr)
+		}
+		if n := client.eventsCalls.Load(); n != 1 {
+			t.Errorf("ListSecurityEvents was called %d times, want once", n)
+		}
+	})
+
+	t.Run("rejects nested fragments before loading", func(t *testing.T) {
+		// Each fragment spreads the next one twice, so that the document expands into 2^30 fields.
+		var query strings.Builder
+		query.WriteString("{ me { ...F0 } }")
+		for i := range 30 {
+			fmt.Fprintf(&query, " fragment F%d on User { ...F%d ...F%d }", i, i+1, i+1)
+		}
+		query.WriteString(" fragment F30 on User { id email }")
+
+		body, err := json.Marshal(map[string]any{"query": query.String()})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+
+		client := &fakeAuthClient{}
+		start := time.Now()
+		status, _ := doGraphQL(t, newGraphQLApp(client), string(body))
+		if status != http.StatusBadRequest {
+			t.Errorf("status is %d, want %d", status, http.StatusBadRequest)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("query was rejected after %s, want it rejected before it is expanded", elapsed)
+		}
+		if client.getMeCalls.Load() != 0 {
+			t.Errorf("GetMe was called for a rejected query")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/healthcheck"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+
+	readinessCheckTimeout = 2 * time.Second
+)
+
+type HealthHTTPHandler struct {
+	checks healthcheck.Checks
+	router fiber.Router
+}
+
+// NewHealthHTTPHandler creates a handler for the Kubernetes probes. The gateway is ready when every
+// dependency in checks is healthy.
+func NewHealthHTTPHandler(checks healthcheck.Checks, router fiber.Router) *HealthHTTPHandler {
+	return &HealthHTTPHandler{
+		checks: checks,
+		router: router,
+	}
+}
+
+func (h *HealthHTTPHandler) RegisterRoutes() {
+	h.router.Get("/healthz", h.Healthz)
+	h.router.Get("/readyz", h.Readyz)
+}
+
+// Healthz reports that the gateway is alive. It does not check dependencies, so that an unhealthy
+// backend service does not get the gateway restarted.
+func (h *HealthHTTPHandler) Healthz(c *fiber.Ctx) error {
+	return response.JSON(c, http.StatusOK, contract.NewSuccessResponse(payload.HealthResponse{
+		Status: healthStatusOK,
+	}))
+}
+
+// Readyz reports whether the gateway can serve requests, with the status of each dependency.
+func (h *HealthHTTPHandler) Readyz(c *fiber.Ctx) error {
+	health := payload.HealthResponse{
+		Status: healthStatusOK,
+		Checks: make(map[string]string, len(h.checks)),
+	}
+
+	for name, err := range h.checks.Run(c.UserContext(), readinessCheckTimeout) {
+		if err == nil {
+			health.Checks[name] = healthStatusOK
+			continue
+		}
+
+		logger.FromContext(c.UserContext()).Warn().Err(err).Str("dependency", name).Msg("Readiness check failed")
+		health.Status = healthStatusUnavailable
+		health.Checks[name] = healthStatusUnavailable
+	}
+
+	if health.Status != healthStatusOK {
+		resp := contract.NewErrorResponse(contract.ErrorCodeUnavailable, "service is not ready")
+		resp.Data = health
+		return response.JSON(c, http.StatusServiceUnavailable, resp)
+	}
+
+	return response.JSON(c, http.StatusOK, contract.NewSuccessResponse(health))
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/realtime"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+)
+
+// RouteMiddleware contains the middleware the gateway's route groups are protected with.
+type RouteMiddleware struct {
+	Auth          fiber.Handler
+	AuthRateLimit fiber.Handler
+	UserRateLimit fiber.Handler
+	AuthBodyLimit fiber.Handler
+	UserBodyLimit fiber.Handler
+	Idempotency   fiber.Handler
+	CSRF          fiber.Handler
+}
+
+// UnversionedOptions schedules the retirement of the routes served without a version prefix. They
+// predate API versioning and are kept, as aliases of v1, for app releases that still call them.
+// Deprecated is when they were deprecated, and Sunset when they will stop being served, or the zero
+// time when it is not decided yet.
+type UnversionedOptions struct {
+	Deprecated time.Time
+	Sunset     time.Time
+}
+
+// RegisterRoutes registers every gateway route on the app and records it in the table. Each API
+// version is served under its own prefix. A version shares the gRPC APIs of the others: when a
+// newer version changes a route, the older version keeps its payloads and adapts them to the gRPC
+// API in its own request and response mappers. The unversioned aliases do not get routes added
+// after they were deprecated, such as the event stream, logout, the batch endpoint and GraphQL.
+func RegisterRoutes(
+	app *fiber.App,
+	table *proxy.Table,
+	authServiceClient *authclient.AuthServiceClient,
+	hub *realtime.Hub,
+	cookies *authcookie.Transport,
+	middleware RouteMiddleware,
+	batch BatchOptions,
+	graphQL GraphQLOptions,
+	unversioned UnversionedOptions,
+) {
+	v1, v2 := app.Group("/v1"), app.Group("/v2")
+
+	unversionedTable := table.Deprecated(proxy.Deprecation{
+		Date:      unversioned.Deprecated,
+		Sunset:    unversioned.Sunset,
+		Successor: "/v1",
+	})
+	registerVersion(app, unversionedTable, authServiceClient, hub, cookies, middleware, 0)
+	registerVersion(v1, table, authServiceClient, hub, cookies, middleware, 1)
+	registerVersion(v2, table, authServiceClient, hub, cookies, middleware, 2)
+
+	for _, router := range []fiber.Router{v1, v2} {
+		router.Use(
+			"/batch",
+			middleware.Auth,
+			middleware.CSRF,
+			middleware.UserRateLimit,
+			middleware.UserBodyLimit,
+		)
+		NewBatchHTTPHandler(app, table, router, batch).RegisterRoutes()
+
+		graphQLRouter := router.Group(
+			"/graphql",
+			middleware.Auth,
+			middleware.CSRF,
+			middleware.UserRateLimit,
+			middleware.UserBodyLimit,
+		)
+		NewGraphQLHTTPHandler(authServiceClient, graphQLRouter, graphQL).RegisterRoutes(table)
+	}
+}
+
+// registerVersion registers the routes of an API version on the router. Version 0 is the unversioned
+// aliases, which serve the v1 payloads but only the routes that predate versioning.
+func registerVersion(
+	router fiber.Router,
+	table *proxy.Table,
+	authServiceClient *authclient.AuthServiceClient,
+	hub *realtime.Hub,
+	cookies *authcookie.Transport,
+	middleware RouteMiddleware,
+	version int,
+) {
+	router.Use("/auth", middleware.AuthRateLimit, middleware.AuthBodyLimit)
+	router.Use(authcookie.RefreshPath, middleware.CSRF)
+
+	authHandler := NewAuthHTTPHandler(authServiceClient, cookies, router)
+	if version >= 2 {
+		authHandler.RegisterV2Routes(table)
+	} else {
+		authHandler.RegisterRoutes(table)
+	}
+
+	meRouter := router.Group(
+		"/me",
+		middleware.Auth,
+		middleware.CSRF,
+		middleware.UserRateLimit,
+		middleware.UserBodyLimit,
+		middleware.Idempotency,
+	)
+	userHandler := NewUserHTTPHandler(authServiceClient, cookies, meRouter, routerPrefix(router)+authcookie.RefreshPath)
+	userHandler.RegisterRoutes(table)
+	NewExportHTTPHandler(authServiceClient, meRouter).RegisterRoutes(table)
+	if version == 0 {
+		return
+	}
+
+	userHandler.RegisterAccountRoutes(table)
+	NewEventsHTTPHandler(hub, meRouter).RegisterRoutes(table)
+}
+
+// routerPrefix returns the path prefix of the router, which is empty for the app itself.
+func routerPrefix(router fiber.Router) string {
+	if group, ok := router.(*fiber.Group); ok {
+		return group.Prefix
+	}
+
+	return ""
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/payload"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+)
+
+// UserHTTPHandler serves the authenticated user's own resources. Its router is expected to be
+// a "/me" group with the auth middleware already applied. refreshPath is the path the refresh
+// token cookie is set with, so that signing out can clear it.
+type UserHTTPHandler struct {
+	authServiceClient *authclient.AuthServiceClient
+	cookies           *authcookie.Transport
+	router            fiber.Router
+	refreshPath       string
+}
+
+func NewUserHTTPHandler(
+	authServiceClient *authclient.AuthServiceClient,
+	cookies *authcookie.Transport,
+	router fiber.Router,
+	refreshPath string,
+) *UserHTTPHandler {
+	return &UserHTTPHandler{
+		authServiceClient: authServiceClient,
+		cookies:           cookies,
+		router:            router,
+		refreshPath:       refreshPath,
+	}
+}
+
+func (h *UserHTTPHandler) RegisterRoutes(table *proxy.Table) {
+	client := h.authServiceClient.Client
+
+	table.RegisterAuthenticated(h.router,
+		proxy.NewRoute(fiber.MethodGet, "/", client.GetMe, toGetMeRequest, toGetMeResponse).
+			WithCache(proxy.Cache{}).
+			WithVersion(userVersion),
+		proxy.NewRoute(fiber.MethodPatch, "/", client.UpdateProfile, toUpdateProfileRequest, toUpdateProfileResponse),
+		proxy.NewRoute(
+			fiber.MethodGet,
+			"/security-events",
+			client.ListSecurityEvents,
+			toListSecurityEventsRequest,
+			toListSecurityEventsResponse,
+		).WithPageInfo(toSecurityEventsPageInfo).WithCache(proxy.Cache{}),
+	)
+}
+
+// RegisterAccountRoutes registers the routes that sign the user out and change their password.
+func (h *UserHTTPHandler) RegisterAccountRoutes(table *proxy.Table) {
+	client := h.authServiceClient.Client
+
+	table.RegisterAuthenticated(h.router,
+		proxy.NewCommand(fiber.MethodPost, "/logout", client.Logout, toLogoutRequest).WithSuccess(h.clearTokens),
+		proxy.NewCommand(fiber.MethodPost, "/password", client.ChangePassword, toChangePasswordRequest),
+	)
+}
+
+func toGetMeRequest(c *fiber.Ctx, _ *proxy.Empty) *authpbv1.GetMeRequest {
+	return &authpbv1.GetMeRequest{
+		UserId: middleware.UserID(c),
+	}
+}
+
+func toGetMeResponse(_ *fiber.Ctx, resp *authpbv1.GetMeResponse) *payload.UserResponse {
+	return toUserResponse(resp.GetUser())
+}
+
+// userVersion identifies the version of the user by its update time, which changes on every update.
+func userVersion(resp *authpbv1.GetMeResponse) string {
+	user := resp.GetUser()
+	return user.GetId() + ":" + strconv.FormatInt(user.GetUpdatedAt().AsTime().UnixNano(), 10)
+}
+
+func toUpdateProfileRequest(c *fiber.Ctx, req *payload.UpdateProfileRequest) *authpbv1.UpdateProfileRequest {
+	return &authpbv1.UpdateProfileRequest{
+		UserId:          middleware.UserID(c),
+		FullName:        req.FullName,
+		DisplayName:     req.DisplayName,
+		AvatarUrl:       req.AvatarURL,
+		Locale:          req.Locale,
+		Timezone:        req.Timezone,
+		DefaultCurrency: req.DefaultCurrency,
+	}
+}
+
+func toUpdateProfileResponse(_ *fiber.Ctx, resp *authpbv1.UpdateProfileResponse) *payload.UserResponse {
+	return toUserResponse(resp.GetUser())
+}
+
+func toLogoutRequest(c *fiber.Ctx, _ *proxy.Empty) *authpbv1.LogoutRequest {
+	return &authpbv1.LogoutRequest{
+		UserId:    middleware.UserID(c),
+		SessionId: middleware.SessionID(c),
+		IpAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+// clearTokens clears the token cookies of clients using the cookie token transport, which cannot
+// delete the HttpOnly cookies themselves.
+func (h *UserHTTPHandler) clearTokens(c *fiber.Ctx, _ *authpbv1.LogoutResponse) {
+	h.cookies.ClearTokens(c, h.refreshPath)
+}
+
+func toChangePasswordRequest(c *fiber.Ctx, req *payload.ChangePasswordRequest) *authpbv1.ChangePasswordRequest {
+	return &authpbv1.ChangePasswordRequest{
+		UserId:          middleware.UserID(c),
+		SessionId:       middleware.SessionID(c),
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+		IpAddress:       c.IP(),
+		UserAgent:       c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+func toListSecurityEventsRequest(
+	c *fiber.Ctx,
+	req *payload.ListSecurityEventsRequest,
+) *authpbv1.ListSecurityEventsRequest {
+	return &authpbv1.ListSecurityEventsRequest{
+		UserId: middleware.UserID(c),
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+	}
+}
+
+func toListSecurityEventsResponse(
+	_ *fiber.Ctx,
+	resp *authpbv1.ListSecurityEventsResponse,
+) []payload.SecurityEventResponse {
+	events := make([]payload.SecurityEventResponse, 0, len(resp.GetEvents()))
+	for _, event := range resp.GetEvents() {
+		events = append(events, toSecurityEventResponse(event))
+	}
+
+	return events
+}
+
+func toSecurityEventResponse(event *authpbv1.SecurityEvent) payload.SecurityEventResponse {
+	return payload.SecurityEventResponse{
+		ID:        event.GetId(),
+		Type:      event.GetType(),
+		SessionID: event.GetSessionId(),
+		IPAddress: event.GetIpAddress(),
+		UserAgent: event.GetUserAgent(),
+		Reason:    event.GetReason(),
+		CreatedAt: event.GetCreatedAt().AsTime(),
+	}
+}
+
+func toSecurityEventsPageInfo(resp *authpbv1.ListSecurityEventsResponse) contract.PageInfo {
+	return contract.PageInfo{
+		NextCursor: resp.GetNextCursor(),
+		HasMore:    resp.GetHasMore(),
+	}
+}
+
+func toUserResponse(user *authpbv1.User) *payload.UserResponse {
+	return &payload.UserResponse{
+		ID:              user.GetId(),
+		Email:           user.GetEmail(),
+		FullName:        user.GetFullName(),
+		Verified:        user.GetVerified(),
+		DisplayName:     user.GetDisplayName(),
+		AvatarURL:       user.GetAvatarUrl(),
+		Locale:          user.GetLocale(),
+		Timezone:        user.GetTimezone(),
+		DefaultCurrency: user.GetDefaultCurrency(),
+		CreatedAt:       user.GetCreatedAt().AsTime(),
+		UpdatedAt:       user.GetUpdatedAt().AsTime(),
+	}
+}
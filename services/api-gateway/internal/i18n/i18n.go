@@ -0,0 +1,151 @@
+// Package i18n localizes the messages of error responses. The language of a request is the locale
+// of the user's profile, when the request is authenticated and the profile has one, or else the
+// best match for the Accept-Language header.
+//
+// Message catalogs are embedded from locales/<locale>.json. A catalog translates error messages,
+// keyed by their English text, and holds validation message templates keyed by validate tag, which
+// override the go-playground defaults of the locale.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/language"
+)
+
+const (
+	English = "en"
+	Thai    = "th"
+
+	// DefaultLocale is used when no preference of the client matches a catalog.
+	DefaultLocale = English
+
+	// profileLocaleKey is the fiber.Ctx locals key holding the locale of the user's profile.
+	profileLocaleKey = "profile_locale"
+)
+
+// Catalog holds the translations of a locale.
+type Catalog struct {
+	// Messages translates error messages, keyed by their English text.
+	Messages map[string]string `json:"messages"`
+	// Validation holds message templates keyed by validate tag, with {0} standing for the field
+	// name and {1} for the tag's parameter.
+	Validation map[string]string `json:"validation"`
+}
+
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+var (
+	catalogs = loadCatalogs()
+	locales  = catalogLocales()
+	matcher  = newMatcher()
+)
+
+// Locales returns the locales that have a catalog, starting with the default locale.
+func Locales() []string {
+	return slices.Clone(locales)
+}
+
+// Match returns the supported locale that best matches the preferences, which are BCP 47 language
+// tags or Accept-Language header values, in order of priority.
+func Match(preferences ...string) string {
+	var tags []language.Tag
+	for _, preference := range preferences {
+		parsed, _, err := language.ParseAcceptLanguage(preference)
+		if err == nil {
+			tags = append(tags, parsed...)
+		}
+	}
+	if len(tags) == 0 {
+		return DefaultLocale
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+
+	return locales[index]
+}
+
+// Locale returns the locale of the request.
+func Locale(c *fiber.Ctx) string {
+	profileLocale, _ := c.Locals(profileLocaleKey).(string)
+
+	return Match(profileLocale, c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// SetProfileLocale records the locale of the authenticated user's profile, which takes precedence
+// over the Accept-Language header.
+func SetProfileLocale(c *fiber.Ctx, locale string) {
+	c.Locals(profileLocaleKey, locale)
+}
+
+// Translate returns the translation of the English message in the locale, or the message itself
+// when the catalog has none.
+func Translate(locale, message string) string {
+	if translation, ok := catalogs[locale].Messages[message]; ok {
+		return translation
+	}
+
+	return message
+}
+
+// ValidationMessages returns the validation message templates of the locale, keyed by validate tag.
+func ValidationMessages(locale string) map[string]string {
+	return catalogs[locale].Validation
+}
+
+// loadCatalogs parses the embedded catalogs. They are part of the binary, so an invalid catalog is
+// a programming error.
+func loadCatalogs() map[string]Catalog {
+	files, err := catalogFiles.ReadDir("locales")
+	if err != nil {
+		panic("i18n: read catalogs: " + err.Error())
+	}
+
+	catalogs := make(map[string]Catalog, len(files))
+	for _, file := range files {
+		data, err := catalogFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic("i18n: read catalog " + file.Name() + ": " + err.Error())
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic("i18n: parse catalog " + file.Name() + ": " + err.Error())
+		}
+		catalogs[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = catalog
+	}
+
+	return catalogs
+}
+
+func catalogLocales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	slices.Sort(locales[1:])
+
+	return locales
+}
+
+// newMatcher creates a matcher over the supported locales. The first, default, locale is chosen when
+// nothing matches.
+func newMatcher() language.Matcher {
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		tags = append(tags, language.MustParse(locale))
+	}
+
+	return language.NewMatcher(tags)
+}
@@ -0,0 +1,84 @@
+package i18n_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences []string
+		want        string
+	}{
+		{name: "no preference", preferences: nil, want: i18n.English},
+		{name: "thai", preferences: []string{"th"}, want: i18n.Thai},
+		{name: "regional variant", preferences: []string{"th-TH"}, want: i18n.Thai},
+		{name: "accept-language order", preferences: []string{"fr;q=0.9, th;q=0.8, en;q=0.5"}, want: i18n.Thai},
+		{name: "unsupported", preferences: []string{"fr"}, want: i18n.English},
+		{name: "profile first", preferences: []string{"th", "en-US,en;q=0.9"}, want: i18n.Thai},
+		{name: "empty profile", preferences: []string{"", "th"}, want: i18n.Thai},
+		{name: "malformed", preferences: []string{"not a language;;"}, want: i18n.English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := i18n.Match(tt.preferences...); got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.preferences, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalePrefersProfile(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		i18n.SetProfileLocale(c, i18n.Thai)
+		return c.SendString(i18n.Locale(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "en-US,en;q=0.9")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if string(body) != i18n.Thai {
+		t.Errorf("Locale() = %q, want %q", body, i18n.Thai)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := i18n.Translate(i18n.Thai, "user not found"); got == "user not found" {
+		t.Errorf("Translate(th) returned the English message")
+	}
+	if got := i18n.Translate(i18n.English, "user not found"); got != "user not found" {
+		t.Errorf("Translate(en) = %q, want the message itself", got)
+	}
+	if got := i18n.Translate(i18n.Thai, "no such message"); got != "no such message" {
+		t.Errorf("Translate(th) of an unknown message = %q, want the message itself", got)
+	}
+}
+
+func TestCatalogsHaveTheSameValidationTags(t *testing.T) {
+	want := i18n.ValidationMessages(i18n.DefaultLocale)
+	for _, locale := range i18n.Locales() {
+		got := i18n.ValidationMessages(locale)
+		for tag := range want {
+			if _, ok := got[tag]; !ok {
+				t.Errorf("catalog %q has no validation message for %q", locale, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package idempotency keeps the responses of mutating requests so that retries carrying the same
+// Idempotency-Key are replayed instead of being executed again.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response is a stored HTTP response.
+type Response struct {
+	Status      int    `bson:"status"`
+	ContentType string `bson:"content_type"`
+	Body        []byte `bson:"body"`
+}
+
+// Record is the state of a claimed idempotency key.
+type Record struct {
+	// Fingerprint identifies the request that claimed the key.
+	Fingerprint string
+	// Response is nil while the request that claimed the key is still in flight.
+	Response *Response
+}
+
+// Store keeps idempotency records.
+//
+// Begin must atomically claim key for a request with the given fingerprint and return a token that
+// identifies the claim, or return the existing record and an empty token when the key is already
+// claimed. Claims expire after lockTimeout so that keys held by requests that never completed can
+// be claimed again. Complete stores the response of the claiming request and keeps it for ttl.
+// Release removes a claim that has no response, so that the request can be retried. Both only act
+// on the claim of token, so that a request that outlived its claim leaves the claim of its retry
+// alone.
+type Store interface {
+	Begin(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (Record, string, error)
+	Complete(ctx context.Context, key, token string, resp Response, ttl time.Duration) error
+	Release(ctx context.Context, key, token string) error
+}
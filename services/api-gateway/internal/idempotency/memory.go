@@ -0,0 +1,98 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often expired records are removed from a MemoryStore.
+const memorySweepInterval = time.Minute
+
+type memoryRecord struct {
+	Record
+
+	token     string
+	expiresAt time.Time
+}
+
+// MemoryStore keeps idempotency records in process memory. Keys are only deduplicated per gateway
+// instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates a new in-memory idempotency store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:   make(map[string]*memoryRecord),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Begin(
+	_ context.Context,
+	key, fingerprint string,
+	lockTimeout time.Duration,
+) (Record, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if record, ok := s.records[key]; ok && now.Before(record.expiresAt) {
+		return record.Record, "", nil
+	}
+
+	token := rand.Text()
+	s.records[key] = &memoryRecord{
+		Record:    Record{Fingerprint: fingerprint},
+		token:     token,
+		expiresAt: now.Add(lockTimeout),
+	}
+
+	return Record{}, token, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key, token string, resp Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; ok && record.token == token && record.Response == nil {
+		record.Response = &resp
+		record.expiresAt = s.now().Add(ttl)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; ok && record.token == token && record.Response == nil {
+		delete(s.records, key)
+	}
+
+	return nil
+}
+
+// sweep removes expired records. It must be called with s.mu held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, record := range s.records {
+		if !now.Before(record.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
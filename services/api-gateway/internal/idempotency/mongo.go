@@ -0,0 +1,125 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	mongoCollection = "idempotency_keys"
+
+	// mongoBeginAttempts bounds how often Begin retries when the record it found disappears
+	// before it could be read.
+	mongoBeginAttempts = 3
+)
+
+type mongoRecord struct {
+	Key         string    `bson:"_id"`
+	Fingerprint string    `bson:"fingerprint"`
+	Token       string    `bson:"token"`
+	Response    *Response `bson:"response,omitempty"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// MongoStore keeps idempotency records in a MongoDB collection. Keys are claimed with a unique
+// insert, so they are deduplicated across gateway instances, and expired records are removed by
+// a TTL index.
+type MongoStore struct {
+	collection *mongo.Collection
+	now        func() time.Time
+}
+
+// NewMongoStore creates a new MongoDB idempotency store, creating the TTL index of its collection.
+func NewMongoStore(ctx context.Context, db *mongo.Database) (*MongoStore, error) {
+	collection := db.Collection(mongoCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoStore{
+		collection: collection,
+		now:        time.Now,
+	}, nil
+}
+
+func (s *MongoStore) Begin(
+	ctx context.Context,
+	key, fingerprint string,
+	lockTimeout time.Duration,
+) (Record, string, error) {
+	for range mongoBeginAttempts {
+		now := s.now()
+		expiresAt := now.Add(lockTimeout)
+		token := rand.Text()
+
+		_, err := s.collection.InsertOne(ctx, mongoRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Token:       token,
+			ExpiresAt:   expiresAt,
+		})
+		if err == nil {
+			return Record{}, token, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return Record{}, "", err
+		}
+
+		// The TTL monitor only runs periodically, so an expired record may still exist. Claim it
+		// as if it were absent.
+		result, err := s.collection.UpdateOne(
+			ctx,
+			bson.M{"_id": key, "expires_at": bson.M{"$lte": now}},
+			bson.M{
+				"$set":   bson.M{"fingerprint": fingerprint, "token": token, "expires_at": expiresAt},
+				"$unset": bson.M{"response": ""},
+			},
+		)
+		if err != nil {
+			return Record{}, "", err
+		}
+		if result.ModifiedCount == 1 {
+			return Record{}, token, nil
+		}
+
+		var record mongoRecord
+		err = s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&record)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return Record{}, "", err
+		}
+
+		return Record{Fingerprint: record.Fingerprint, Response: record.Response}, "", nil
+	}
+
+	return Record{}, "", errors.New("failed to claim idempotency key")
+}
+
+func (s *MongoStore) Complete(ctx context.Context, key, token string, resp Response, ttl time.Duration) error {
+	_, err := s.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": key, "token": token, "response": nil},
+		bson.M{"$set": bson.M{"response": resp, "expires_at": s.now().Add(ttl)}},
+	)
+
+	return err
+}
+
+func (s *MongoStore) Release(ctx context.Context, key, token string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key, "token": token, "response": nil})
+
+	return err
+}
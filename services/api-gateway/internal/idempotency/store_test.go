@@ -0,0 +1,199 @@
+package idempotency
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/clocktest"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	lockTimeout = time.Minute
+	ttl         = time.Hour
+)
+
+// backends opens each Store implementation on the given clock.
+var backends = []struct {
+	name string
+	open func(*testing.T, *clocktest.Clock) Store
+}{
+	{name: "memory", open: openMemoryStore},
+	{name: "mongo", open: openMongoStore},
+}
+
+func openMemoryStore(_ *testing.T, clock *clocktest.Clock) Store {
+	store := NewMemoryStore()
+	store.now = clock.Now
+
+	return store
+}
+
+// openMongoStore returns a store on a throwaway database on the MongoDB instance at MONGO_TEST_URI.
+// Tests are skipped when the variable is not set.
+func openMongoStore(t *testing.T, clock *clocktest.Clock) Store {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	db := client.Database("gateway_idempotency_" + bson.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	store, err := NewMongoStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	store.now = clock.Now
+
+	return store
+}
+
+// claim claims key for the request with fingerprint and returns the token of the claim.
+func claim(t *testing.T, store Store, key, fingerprint string) string {
+	t.Helper()
+
+	record, token, err := store.Begin(context.Background(), key, fingerprint, lockTimeout)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if token == "" {
+		t.Fatalf("key %q is already claimed by %+v", key, record)
+	}
+
+	return token
+}
+
+// existing returns the record of key, which must already be claimed.
+func existing(t *testing.T, store Store, key, fingerprint string) Record {
+	t.Helper()
+
+	record, token, err := store.Begin(context.Background(), key, fingerprint, lockTimeout)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if token != "" {
+		t.Fatalf("key %q was claimed, want the existing record", key)
+	}
+
+	return record
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Run("returns the in-flight record to later requests", func(t *testing.T) {
+				store := backend.open(t, clocktest.New())
+
+				claim(t, store, "key", "a")
+				if record := existing(t, store, "key", "b"); record.Fingerprint != "a" || record.Response != nil {
+					t.Fatalf("record is %+v, want the in-flight claim of a", record)
+				}
+			})
+
+			t.Run("returns the stored response once completed", func(t *testing.T) {
+				store := backend.open(t, clocktest.New())
+
+				token := claim(t, store, "key", "a")
+				resp := Response{Status: 201, ContentType: "application/json", Body: []byte(`{}`)}
+				if err := store.Complete(ctx, "key", token, resp, ttl); err != nil {
+					t.Fatalf("Complete: %v", err)
+				}
+
+				record := existing(t, store, "key", "a")
+				if record.Response == nil || record.Response.Status != 201 || string(record.Response.Body) != `{}` {
+					t.Fatalf("response is %+v, want the stored response", record.Response)
+				}
+			})
+
+			t.Run("allows the key to be claimed again after a release", func(t *testing.T) {
+				store := backend.open(t, clocktest.New())
+
+				token := claim(t, store, "key", "a")
+				if err := store.Release(ctx, "key", token); err != nil {
+					t.Fatalf("Release: %v", err)
+				}
+				claim(t, store, "key", "a")
+			})
+
+			t.Run("allows abandoned and expired keys to be claimed again", func(t *testing.T) {
+				clock := clocktest.New()
+				store := backend.open(t, clock)
+
+				claim(t, store, "key", "a")
+				clock.Advance(lockTimeout)
+				token := claim(t, store, "key", "b")
+
+				if err := store.Complete(ctx, "key", token, Response{Status: 200}, ttl); err != nil {
+					t.Fatalf("Complete: %v", err)
+				}
+				clock.Advance(ttl)
+				claim(t, store, "key", "c")
+			})
+
+			t.Run("keeps the claim of a retry from the request it replaced", func(t *testing.T) {
+				clock := clocktest.New()
+				store := backend.open(t, clock)
+
+				abandoned := claim(t, store, "key", "a")
+				clock.Advance(lockTimeout)
+				claim(t, store, "key", "a")
+
+				if err := store.Release(ctx, "key", abandoned); err != nil {
+					t.Fatalf("Release: %v", err)
+				}
+				if err := store.Complete(ctx, "key", abandoned, Response{Status: 200}, ttl); err != nil {
+					t.Fatalf("Complete: %v", err)
+				}
+				if record := existing(t, store, "key", "a"); record.Response != nil {
+					t.Fatalf("response is %+v, want the retry still in flight", record.Response)
+				}
+			})
+
+			t.Run("lets exactly one concurrent request claim a key", func(t *testing.T) {
+				store := backend.open(t, clocktest.New())
+
+				var claimed atomic.Int32
+				var wg sync.WaitGroup
+				for range 10 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						_, token, err := store.Begin(ctx, "key", "a", lockTimeout)
+						if err != nil {
+							t.Errorf("Begin: %v", err)
+						}
+						if token != "" {
+							claimed.Add(1)
+						}
+					}()
+				}
+				wg.Wait()
+
+				if n := claimed.Load(); n != 1 {
+					t.Fatalf("%d requests claimed the key, want 1", n)
+				}
+			})
+		})
+	}
+}
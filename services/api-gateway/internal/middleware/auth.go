@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/auth"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+)
+
+const (
+	// UserIDKey is the fiber.Ctx locals key holding the authenticated user ID.
+	UserIDKey = "user_id"
+	// SessionIDKey is the fiber.Ctx locals key holding the authenticated session ID.
+	SessionIDKey = "session_id"
+	// TokenExpiresAtKey is the fiber.Ctx locals key holding the expiry time of the access token.
+	TokenExpiresAtKey = "token_expires_at"
+)
+
+const bearerPrefix = "Bearer "
+
+// NewAuthMiddleware creates a middleware that requires a valid access token and stores the token's
+// user and session IDs in the request locals. The token is read from the Authorization header or,
+// for requests without one, from the access token cookie.
+func NewAuthMiddleware(
+	authenticator auth.Authenticator,
+	accessTokenSecret string,
+	cookies *authcookie.Transport,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accessToken := requestAccessToken(c, cookies)
+		if accessToken == "" {
+			return response.JSON(
+				c,
+				http.StatusUnauthorized,
+				contract.NewErrorResponse(contract.ErrorCodeUnauthorized, "missing access token"),
+			)
+		}
+
+		token, err := authenticator.ValidateToken(accessToken, accessTokenSecret)
+		if err != nil {
+			return response.JSON(
+				c,
+				http.StatusUnauthorized,
+				contract.NewErrorResponse(contract.ErrorCodeUnauthorized, "invalid access token"),
+			)
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return response.JSON(
+				c,
+				http.StatusUnauthorized,
+				contract.NewErrorResponse(contract.ErrorCodeUnauthorized, "invalid access token"),
+			)
+		}
+
+		userID, _ := claims["user_id"].(string)
+		if userID == "" {
+			return response.JSON(
+				c,
+				http.StatusUnauthorized,
+				contract.NewErrorResponse(contract.ErrorCodeUnauthorized, "invalid access token"),
+			)
+		}
+		sessionID, _ := claims["session_id"].(string)
+		locale, _ := claims["locale"].(string)
+
+		c.Locals(UserIDKey, userID)
+		c.Locals(SessionIDKey, sessionID)
+		if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+			c.Locals(TokenExpiresAtKey, expiresAt.Time)
+		}
+		i18n.SetProfileLocale(c, locale)
+
+		return c.Next()
+	}
+}
+
+// TokenExpiresAt returns when the access token of the request expires, or the zero time when it
+// does not expire.
+func TokenExpiresAt(c *fiber.Ctx) time.Time {
+	expiresAt, _ := c.Locals(TokenExpiresAtKey).(time.Time)
+	return expiresAt
+}
+
+// requestAccessToken returns the bearer token of the Authorization header, or the access token
+// cookie when the header is absent.
+func requestAccessToken(c *fiber.Ctx, cookies *authcookie.Transport) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if header == "" {
+		return cookies.AccessToken(c)
+	}
+
+	accessToken, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok {
+		return ""
+	}
+
+	return accessToken
+}
+
+// UserID returns the authenticated user ID stored by the auth middleware.
+func UserID(c *fiber.Ctx) string {
+	userID, _ := c.Locals(UserIDKey).(string)
+	return userID
+}
+
+// SessionID returns the authenticated session ID stored by the auth middleware.
+func SessionID(c *fiber.Ctx) string {
+	sessionID, _ := c.Locals(SessionIDKey).(string)
+	return sessionID
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/valyala/fasthttp"
+)
+
+// eventStreamContentType is the content type of Server-Sent Events, which are written to the client
+// as they happen and must not be buffered by compression.
+const eventStreamContentType = "text/event-stream"
+
+// encodingSuffix matches the content coding the middleware appends to the strong ETags of the
+// responses it compresses, e.g. the "-br" of "abc-br". Gateway ETags are hex, so they contain no
+// dash of their own.
+var encodingSuffix = regexp.MustCompile(`-(?:br|gzip|deflate)"`)
+
+// compressionLevels maps the compression levels to the brotli and gzip levels they use.
+var compressionLevels = map[compress.Level][2]int{
+	compress.LevelDefault:         {fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression},
+	compress.LevelBestSpeed:       {fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed},
+	compress.LevelBestCompression: {fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression},
+}
+
+// NewCompressionMiddleware creates a middleware that compresses responses with brotli or gzip,
+// whichever the client prefers, at the level. Small bodies, event streams and WebSocket connections
+// are sent as they are.
+//
+// A strong ETag identifies the bytes of one representation, so the middleware appends the content
+// coding to the ETag of the responses it compresses, and removes it from If-None-Match before the
+// request is handled. Routes compare If-None-Match with the ETag of the uncompressed content.
+func NewCompressionMiddleware(level compress.Level) fiber.Handler {
+	levels, ok := compressionLevels[level]
+	if !ok {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	compressor := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {}, levels[0], levels[1])
+
+	return func(c *fiber.Ctx) error {
+		if websocket.IsWebSocketUpgrade(c) {
+			return c.Next()
+		}
+
+		ifNoneMatch := strings.Clone(c.Get(fiber.HeaderIfNoneMatch))
+		if ifNoneMatch != "" {
+			c.Request().Header.Set(fiber.HeaderIfNoneMatch, encodingSuffix.ReplaceAllString(ifNoneMatch, `"`))
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		header := &c.Response().Header
+		if header.StatusCode() == http.StatusNotModified {
+			restoreETag(c, ifNoneMatch)
+			return nil
+		}
+		if len(header.ContentEncoding()) > 0 || bytes.HasPrefix(header.ContentType(), []byte(eventStreamContentType)) {
+			return nil
+		}
+
+		compressor(c.Context())
+
+		etag := string(header.Peek(fiber.HeaderETag))
+		encoding := string(header.ContentEncoding())
+		if encoding != "" && strings.HasPrefix(etag, `"`) {
+			c.Set(fiber.HeaderETag, strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`)
+		}
+
+		return nil
+	}
+}
+
+// restoreETag sends back the ETag of a Not Modified response as the client has it, with the content
+// coding of the representation it cached.
+func restoreETag(c *fiber.Ctx, ifNoneMatch string) {
+	etag := string(c.Response().Header.Peek(fiber.HeaderETag))
+	if etag == "" {
+		return
+	}
+
+	for tag := range strings.SplitSeq(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if encodingSuffix.ReplaceAllString(tag, `"`) == etag {
+			c.Set(fiber.HeaderETag, tag)
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/middleware"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	body := strings.Repeat("moneylog ", 100)
+
+	app := fiber.New()
+	app.Use(middleware.NewCompressionMiddleware(compress.LevelDefault))
+	app.Get("/data", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderETag, `"abc"`)
+		if response.ETagMatches(c.Get(fiber.HeaderIfNoneMatch), `"abc"`) {
+			return c.SendStatus(http.StatusNotModified)
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(body)
+	})
+	app.Get("/events", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		return c.SendString(body)
+	})
+
+	get := func(t *testing.T, path, encoding, ifNoneMatch string) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAcceptEncoding, encoding)
+		if ifNoneMatch != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		encoding    string
+		ifNoneMatch string
+		status      int
+		wantEncoded string
+		wantETag    string
+	}{
+		{
+			name:        "brotli",
+			path:        "/data",
+			encoding:    "gzip, br",
+			status:      http.StatusOK,
+			wantEncoded: "br",
+			wantETag:    `"abc-br"`,
+		},
+		{
+			name:        "gzip",
+			path:        "/data",
+			encoding:    "gzip",
+			status:      http.StatusOK,
+			wantEncoded: "gzip",
+			wantETag:    `"abc-gzip"`,
+		},
+		{name: "identity", path: "/data", status: http.StatusOK, wantETag: `"abc"`},
+		{
+			name:        "not modified",
+			path:        "/data",
+			encoding:    "gzip",
+			ifNoneMatch: `"xyz", "abc-gzip"`,
+			status:      http.StatusNotModified,
+			wantETag:    `"abc-gzip"`,
+		},
+		{name: "event stream", path: "/events", encoding: "gzip", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, tt.path, tt.encoding, tt.ifNoneMatch)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status is %d, want %d", resp.StatusCode, tt.status)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentEncoding); got != tt.wantEncoded {
+				t.Errorf("Content-Encoding is %q, want %q", got, tt.wantEncoded)
+			}
+			if got := resp.Header.Get(fiber.HeaderETag); got != tt.wantETag {
+				t.Errorf("ETag is %q, want %q", got, tt.wantETag)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+)
+
+// NewCSRFMiddleware creates a middleware that protects state-changing requests authenticated by
+// the token cookies with a double-submit CSRF token: the X-CSRF-Token header must match the CSRF
+// cookie. Requests with an Authorization header are not authenticated by cookies and are not checked.
+func NewCSRFMiddleware(cookies *authcookie.Transport) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		if c.Get(fiber.HeaderAuthorization) != "" || !cookies.Authenticated(c) {
+			return c.Next()
+		}
+
+		if !cookies.ValidCSRF(c) {
+			return response.JSON(
+				c,
+				http.StatusForbidden,
+				contract.NewErrorResponse(contract.ErrorCodeForbidden, "missing or invalid CSRF token"),
+			)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/idempotency"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyRetryAfter is the Retry-After, in seconds, sent when a request with the same key is in flight.
+	idempotencyRetryAfter = 1
+)
+
+// IdempotencyPolicy describes how long idempotency keys are kept. Responses are kept for TTL, and
+// a key whose request has not completed within LockTimeout may be claimed by a retry.
+type IdempotencyPolicy struct {
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+// NewIdempotencyMiddleware creates a middleware that makes mutating requests carrying an
+// Idempotency-Key header safe to retry. The first response for a user, route and key is stored and
+// replayed for retries with the same body, while retries with a different body, and duplicates
+// that arrive while the first request is in flight, are rejected with 409 Conflict. Server errors
+// are not stored, so that the request can be retried. It must run after the auth middleware.
+func NewIdempotencyMiddleware(store idempotency.Store, policy IdempotencyPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		idempotencyKey := c.Get(HeaderIdempotencyKey)
+		if idempotencyKey == "" {
+			return c.Next()
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return response.JSON(
+				c,
+				http.StatusBadRequest,
+				contract.NewErrorResponse(contract.ErrorCodeBadRequest, "idempotency key is too long"),
+			)
+		}
+
+		ctx := c.UserContext()
+		key := hash(idempotencyScope(c), c.Method(), c.Path(), idempotencyKey)
+		fingerprint := hash(string(c.Body()))
+
+		record, token, err := store.Begin(ctx, key, fingerprint, policy.LockTimeout)
+		if err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Failed to claim idempotency key")
+			return response.JSON(
+				c,
+				http.StatusServiceUnavailable,
+				contract.NewErrorResponse(contract.ErrorCodeUnavailable, "service unavailable"),
+			)
+		}
+
+		if token == "" {
+			return replay(c, record, fingerprint)
+		}
+
+		if err := c.Next(); err != nil {
+			release(c, store, key, token)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= http.StatusInternalServerError {
+			release(c, store, key, token)
+			return nil
+		}
+
+		resp := idempotency.Response{
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := store.Complete(ctx, key, token, resp, policy.TTL); err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Failed to store idempotent response")
+		}
+
+		return nil
+	}
+}
+
+// replay answers a request whose key was already claimed.
+func replay(c *fiber.Ctx, record idempotency.Record, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		return response.JSON(
+			c,
+			http.StatusConflict,
+			contract.NewErrorResponse(
+				contract.ErrorCodeConflict,
+				"idempotency key was already used with a different request",
+			),
+		)
+	}
+
+	if record.Response == nil {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(idempotencyRetryAfter))
+		return response.JSON(
+			c,
+			http.StatusConflict,
+			contract.NewErrorResponse(
+				contract.ErrorCodeConflict,
+				"a request with this idempotency key is in progress",
+			),
+		)
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, record.Response.ContentType)
+
+	return c.Status(record.Response.Status).Send(record.Response.Body)
+}
+
+func release(c *fiber.Ctx, store idempotency.Store, key, token string) {
+	if err := store.Release(c.UserContext(), key, token); err != nil {
+		logger.FromContext(c.UserContext()).Error().Err(err).Msg("Failed to release idempotency key")
+	}
+}
+
+// idempotencyScope returns the identity idempotency keys are scoped to.
+func idempotencyScope(c *fiber.Ctx) string {
+	if userID := UserID(c); userID != "" {
+		return "user:" + userID
+	}
+
+	return "ip:" + c.IP()
+}
+
+// hash returns the hex encoded SHA-256 of the NUL separated parts.
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+)
+
+// NewMetricsMiddleware creates a middleware that records the duration and status code of every
+// request, labelled with the matched route pattern.
+func NewMetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		metrics.ObserveHTTPRequest(c.Method(), c.Route().Path, status, time.Since(start))
+
+		return err
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+)
+
+// NewRequestIDMiddleware creates a middleware that accepts the client's X-Request-ID or
+// generates a new one, echoes it in the response and stores it, together with a logger that
+// writes it on every line, in the request's user context.
+func NewRequestIDMiddleware(logger *zerolog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set(requestid.Header, id)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), id, logger))
+
+		return c.Next()
+	}
+}
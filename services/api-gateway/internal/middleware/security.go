@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+)
+
+// HeaderLastEventID is the header EventSource clients send when they reconnect to an event stream.
+const HeaderLastEventID = "Last-Event-ID"
+
+// corsAllowedHeaders are the request headers browsers may send cross-origin.
+var corsAllowedHeaders = []string{
+	fiber.HeaderAuthorization,
+	fiber.HeaderContentType,
+	fiber.HeaderAcceptLanguage,
+	fiber.HeaderIfNoneMatch,
+	HeaderIdempotencyKey,
+	HeaderAPIKey,
+	requestid.Header,
+	authcookie.HeaderCSRFToken,
+	authcookie.HeaderTokenTransport,
+	HeaderLastEventID,
+}
+
+// corsExposedHeaders are the response headers cross-origin scripts may read.
+var corsExposedHeaders = []string{
+	fiber.HeaderRetryAfter,
+	fiber.HeaderETag,
+	HeaderIdempotentReplayed,
+	HeaderRateLimitLimit,
+	HeaderRateLimitRemaining,
+	HeaderRateLimitReset,
+	HeaderRateLimitPolicy,
+	requestid.Header,
+	authcookie.HeaderCSRFToken,
+}
+
+// NewCORSMiddleware creates a middleware that lets browsers call the gateway from the allowed
+// origins, with credentials so that the token cookies are sent. Without allowed origins, only
+// same-origin requests are possible and the middleware does nothing.
+func NewCORSMiddleware(allowedOrigins []string, maxAge time.Duration) fiber.Handler {
+	if len(allowedOrigins) == 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(allowedOrigins, ","),
+		AllowHeaders:     strings.Join(corsAllowedHeaders, ","),
+		ExposeHeaders:    strings.Join(corsExposedHeaders, ","),
+		AllowCredentials: true,
+		MaxAge:           int(maxAge.Seconds()),
+	})
+}
+
+// NewSecurityHeadersMiddleware creates a middleware that sets the security headers of every
+// response: the content security policy, X-Content-Type-Options: nosniff, and, for requests
+// received over HTTPS, Strict-Transport-Security with the max age. A zero max age disables HSTS.
+func NewSecurityHeadersMiddleware(hstsMaxAge time.Duration, contentSecurityPolicy string) fiber.Handler {
+	return helmet.New(helmet.Config{
+		ContentSecurityPolicy:     contentSecurityPolicy,
+		HSTSMaxAge:                int(hstsMaxAge.Seconds()),
+		CrossOriginResourcePolicy: "same-site",
+	})
+}
+
+// NewBodyLimitMiddleware creates a middleware that rejects requests whose body is larger than limit
+// bytes with 413 Payload Too Large. It lets route groups accept less than the app's body limit.
+func NewBodyLimitMiddleware(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Request().Header.ContentLength() > limit || len(c.Body()) > limit {
+			return response.JSON(
+				c,
+				http.StatusRequestEntityTooLarge,
+				contract.NewErrorResponse(contract.ErrorCodePayloadTooLarge, "request body is too large"),
+			)
+		}
+
+		return c.Next()
+	}
+}
+
+// NewWebSocketOriginMiddleware creates a middleware that rejects WebSocket upgrades from pages of
+// other origins than the gateway's own and the allowed origins with 403 Forbidden. Browsers apply
+// neither CORS nor SameSite restrictions to WebSocket handshakes, so without the check any site
+// could open a cookie-authenticated connection. Clients that send no Origin are not browsers.
+func NewWebSocketOriginMiddleware(allowedOrigins []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		if !websocket.IsWebSocketUpgrade(c) || origin == "" || origin == c.BaseURL() ||
+			slices.Contains(allowedOrigins, origin) {
+			return c.Next()
+		}
+
+		return response.JSON(
+			c,
+			http.StatusForbidden,
+			contract.NewErrorResponse(contract.ErrorCodeForbidden, "origin is not allowed"),
+		)
+	}
+}
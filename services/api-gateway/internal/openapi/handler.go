@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	swaggerfiles "github.com/swaggo/files/v2"
+)
+
+var (
+	//go:embed docs.html
+	docsPage []byte
+	//go:embed docs.js
+	docsScript []byte
+)
+
+// docsAssetsMaxAge is how long browsers cache the Swagger UI files, which only change with the binary.
+const docsAssetsMaxAge = 24 * time.Hour
+
+// docsContentSecurityPolicy replaces the gateway's policy on the docs page, which loads Swagger UI
+// from the gateway itself. Swagger UI sets inline styles on the elements it renders.
+const docsContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data:; connect-src 'self'"
+
+// Register serves the document at /openapi.json and a Swagger UI page rendering it at /docs. The
+// Swagger UI bundle is embedded in the binary, so the page loads no third-party script.
+func Register(router fiber.Router, doc *Document) {
+	router.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.JSON(doc)
+	})
+	router.Get("/docs", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		c.Set(fiber.HeaderContentSecurityPolicy, docsContentSecurityPolicy)
+		return c.Send(docsPage)
+	})
+	router.Get("/docs/docs.js", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJavaScriptCharsetUTF8)
+		return c.Send(docsScript)
+	})
+	router.Use("/docs/assets", filesystem.New(filesystem.Config{
+		Root:   http.FS(swaggerfiles.FS),
+		MaxAge: int(docsAssetsMaxAge.Seconds()),
+	}))
+}
@@ -0,0 +1,299 @@
+// Package openapi generates the gateway's OpenAPI 3.1 document from its route table.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+)
+
+const (
+	version = "3.1.0"
+
+	bearerAuth        = "bearerAuth"
+	envelopeSchema    = "APIResponse"
+	jsonContentType   = "application/json"
+	defaultTitle      = "Moneylog API"
+	defaultAPIVersion = "1.0.0"
+)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the metadata of the API.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of a path by lower-case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter describes a path, query or header parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the content of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas and security schemes.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how an operation is authenticated.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Generate creates the OpenAPI document describing the operations. Responses are wrapped in the
+// contract.APIResponse envelope, with the operation's response type as its data.
+func Generate(operations []proxy.Operation) *Document {
+	g := newGenerator()
+	g.schemaRef(reflect.TypeFor[contract.APIResponse]())
+
+	doc := &Document{
+		OpenAPI: version,
+		Info:    Info{Title: defaultTitle, Version: defaultAPIVersion},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	operationIDs := make(map[string]int)
+	for _, op := range operations {
+		path := openAPIPath(op.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+
+		operation := g.operation(op)
+
+		operationIDs[operation.OperationID]++
+		if n := operationIDs[operation.OperationID]; n > 1 {
+			operation.OperationID += strconv.Itoa(n)
+		}
+
+		doc.Paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	return doc
+}
+
+func (g *generator) operation(op proxy.Operation) *Operation {
+	name := strings.TrimSuffix(op.Name, "Request")
+	summary := op.Summary
+	if summary == "" {
+		summary = sentence(name)
+	}
+
+	operation := &Operation{
+		OperationID: lowerFirst(name) + strings.ToUpper(op.Version),
+		Summary:     summary,
+		Tags:        tags(strings.TrimPrefix(op.Path, "/"+op.Version)),
+		Deprecated:  op.Deprecation != nil,
+		Parameters:  g.parameters(op.Request),
+		Responses: map[string]Response{
+			strconv.Itoa(op.Status): g.successResponse(op),
+			strconv.Itoa(http.StatusBadRequest): {
+				Description: "The request is invalid.",
+				Content:     jsonContent(&Schema{Ref: componentRef(envelopeSchema)}),
+			},
+			"default": {
+				Description: "The request failed.",
+				Content:     jsonContent(&Schema{Ref: componentRef(envelopeSchema)}),
+			},
+		},
+	}
+
+	if op.Method != http.MethodGet && op.Method != http.MethodDelete {
+		if body := g.requestBody(op.Request); body != nil {
+			operation.RequestBody = &RequestBody{Required: true, Content: jsonContent(body)}
+		}
+	}
+
+	if op.Cache != nil {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:   "If-None-Match",
+			In:     "header",
+			Schema: &Schema{Type: "string"},
+		})
+		operation.Responses[strconv.Itoa(http.StatusNotModified)] = Response{
+			Description: "The response has not changed since the ETag in If-None-Match.",
+		}
+	}
+
+	if op.Authenticated {
+		operation.Security = []map[string][]string{{bearerAuth: {}}}
+		operation.Responses[strconv.Itoa(http.StatusUnauthorized)] = Response{
+			Description: "The access token is missing or invalid.",
+			Content:     jsonContent(&Schema{Ref: componentRef(envelopeSchema)}),
+		}
+	}
+
+	return operation
+}
+
+func (g *generator) successResponse(op proxy.Operation) Response {
+	if op.ContentType != "" {
+		schema := &Schema{}
+		if op.Response != nil {
+			schema = g.schema(op.Response)
+		}
+
+		return Response{
+			Description: "Successful response.",
+			Content:     map[string]MediaType{op.ContentType: {Schema: schema}},
+		}
+	}
+
+	schema := &Schema{Ref: componentRef(envelopeSchema)}
+	if op.Response != nil {
+		schema = &Schema{AllOf: []*Schema{
+			schema,
+			{
+				Type:       "object",
+				Properties: map[string]*Schema{"data": g.schema(op.Response)},
+			},
+		}}
+	}
+
+	return Response{Description: "Successful response.", Content: jsonContent(schema)}
+}
+
+func (g *generator) parameters(t reflect.Type) []Parameter {
+	var params []Parameter
+	for _, field := range fields(t) {
+		for _, in := range []string{"path", "query"} {
+			tag := "params"
+			if in == "query" {
+				tag = "query"
+			}
+
+			name := tagName(field.Tag.Get(tag))
+			if name == "" {
+				continue
+			}
+
+			schema := g.schema(field.Type)
+			required := applyValidation(schema, field.Type, field.Tag.Get("validate"))
+			params = append(params, Parameter{
+				Name:     name,
+				In:       in,
+				Required: required || in == "path",
+				Schema:   schema,
+			})
+		}
+	}
+
+	return params
+}
+
+// requestBody returns the schema of the request's JSON body, or nil when it reads nothing from the body.
+func (g *generator) requestBody(t reflect.Type) *Schema {
+	for _, field := range fields(t) {
+		if _, ok := jsonName(field); ok {
+			return g.schemaRef(t)
+		}
+	}
+
+	return nil
+}
+
+func openAPIPath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+func tags(path string) []string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if segment == "" {
+		return nil
+	}
+
+	return []string{segment}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{jsonContentType: {Schema: schema}}
+}
+
+func componentRef(name string) string {
+	return fmt.Sprintf("#/components/schemas/%s", name)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// sentence turns a CamelCase name into a sentence, e.g. "ExportUserData" into "Export user data".
+func sentence(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteRune(' ')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/authcookie"
+	httphandler "github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/delivery/http"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/openapi"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	authclient "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/client"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+)
+
+const specPath = "../../api/openapi.json"
+
+var update = flag.Bool("update", false, "regenerate the committed OpenAPI document")
+
+func next(c *fiber.Ctx) error {
+	return c.Next()
+}
+
+// TestSpecIsUpToDate fails when the committed document no longer matches the gateway's routes. Run
+// `go test ./services/api-gateway/internal/openapi -update` to regenerate it.
+func TestSpecIsUpToDate(t *testing.T) {
+	table := proxy.NewTable()
+	httphandler.RegisterRoutes(
+		fiber.New(),
+		table,
+		&authclient.AuthServiceClient{Client: authpbv1.NewAuthServiceClient(nil)},
+		nil,
+		authcookie.NewTransport(authcookie.Options{}),
+		httphandler.RouteMiddleware{
+			Auth:          next,
+			AuthRateLimit: next,
+			UserRateLimit: next,
+			AuthBodyLimit: next,
+			UserBodyLimit: next,
+			Idempotency:   next,
+			CSRF:          next,
+		},
+		httphandler.BatchOptions{},
+		httphandler.GraphQLOptions{},
+		httphandler.UnversionedOptions{},
+	)
+
+	got, err := json.MarshalIndent(openapi.Generate(table.Operations()), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.WriteFile(specPath, got, 0o600); err != nil {
+			t.Fatalf("write %s: %v", specPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", specPath, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s is out of date; rerun with -update to regenerate it", specPath)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	app := fiber.New()
+	openapi.Register(app, &openapi.Document{})
+
+	for _, tt := range []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/docs", contentType: fiber.MIMETextHTML},
+		{path: "/docs/docs.js", contentType: fiber.MIMEApplicationJavaScript},
+		{path: "/docs/assets/swagger-ui-bundle.js", contentType: "javascript"},
+		{path: "/docs/assets/swagger-ui.css", contentType: "text/css"},
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status of %s is %d, want %d", tt.path, resp.StatusCode, http.StatusOK)
+		}
+		if got := resp.Header.Get(fiber.HeaderContentType); !strings.Contains(got, tt.contentType) {
+			t.Errorf("content type of %s is %q, want %q", tt.path, got, tt.contentType)
+		}
+		if tt.path == "/docs" {
+			if csp := resp.Header.Get(fiber.HeaderContentSecurityPolicy); strings.Contains(csp, "https:") {
+				t.Errorf("content security policy %q allows another origin", csp)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema as used by OpenAPI 3.1.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// formats maps validate tags to the schema format they imply.
+var formats = map[string]string{
+	"email":              "email",
+	"url":                "uri",
+	"uri":                "uri",
+	"uuid":               "uuid",
+	"ip":                 "ip",
+	"datetime":           "date-time",
+	"timezone":           "timezone",
+	"bcp47_language_tag": "bcp47",
+	"date":               "date",
+}
+
+// patterns maps validate tags to the pattern they imply.
+var patterns = map[string]string{
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"iso4217":     "^[A-Z]{3}$",
+	"objectid":    "^[0-9a-fA-F]{24}$",
+	"hex_color":   "^#[0-9a-fA-F]{6}$",
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	// rawMessageType holds any JSON value, so its schema is unconstrained rather than that of a byte slice.
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// generator builds schemas, collecting named struct types as reusable components.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema)}
+}
+
+// schema returns the schema of t, referring to a component for named structs.
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return g.schemaRef(t)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// schemaRef registers the named struct t as a component and returns a reference to it.
+func (g *generator) schemaRef(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, ok := g.schemas[name]; !ok {
+		// Register a placeholder first so that recursive types terminate.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.object(t)
+	}
+
+	return &Schema{Ref: componentRef(name)}
+}
+
+// object returns the schema of the fields of struct t that are encoded as JSON.
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		property := g.schema(field.Type)
+		if applyValidation(property, field.Type, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// fields returns the exported fields of struct t.
+func fields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := range t.NumField() {
+		if field := t.Field(i); field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// jsonName returns the name of the field in JSON, or false when the field is not read from or
+// written to a JSON body, either because it is ignored or because it is bound to a path parameter
+// or query string.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		if field.Tag.Get("params") != "" || field.Tag.Get("query") != "" {
+			return "", false
+		}
+		return field.Name, true
+	}
+
+	name := tagName(tag)
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// applyValidation adds the constraints of the validate tag to the schema of a value of type t and
+// reports whether the value is required. Tags that have no JSON Schema equivalent are ignored.
+func applyValidation(schema *Schema, t reflect.Type, tag string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var required bool
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// Rules after dive apply to the elements, which are not described further.
+			return required
+		case "required":
+			required = true
+		case "len":
+			setBounds(schema, t, param, param)
+		case "min", "gte":
+			setBounds(schema, t, param, "")
+		case "max", "lte":
+			setBounds(schema, t, "", param)
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "decimal":
+			schema.Pattern = decimalPattern(param)
+		default:
+			if format, ok := formats[name]; ok {
+				schema.Format = format
+			}
+			if pattern, ok := patterns[name]; ok {
+				schema.Pattern = pattern
+			}
+		}
+	}
+
+	return required
+}
+
+// decimalPattern returns the pattern of decimal strings with at most scale fractional digits.
+func decimalPattern(scale string) string {
+	if scale == "0" {
+		return "^-?[0-9]+$"
+	}
+
+	return "^-?[0-9]+(?:\\.[0-9]{1," + scale + "})?$"
+}
+
+// setBounds sets the length, item count or value bounds of the schema, depending on the kind of
+// t, as go-playground/validator interprets min, max and len.
+func setBounds(schema *Schema, t reflect.Type, minParam, maxParam string) {
+	switch t.Kind() {
+	case reflect.String:
+		setInt(&schema.MinLength, minParam)
+		setInt(&schema.MaxLength, maxParam)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		setInt(&schema.MinItems, minParam)
+		setInt(&schema.MaxItems, maxParam)
+	default:
+		setFloat(&schema.Minimum, minParam)
+		setFloat(&schema.Maximum, maxParam)
+	}
+}
+
+func setInt(dst **int, param string) {
+	if n, err := strconv.Atoi(param); err == nil {
+		*dst = &n
+	}
+}
+
+func setFloat(dst **float64, param string) {
+	if n, err := strconv.ParseFloat(param, 64); err == nil {
+		*dst = &n
+	}
+}
@@ -5,9 +5,11 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// LoginResponse holds the issued tokens. They are omitted when the client asked for the cookie
+// token transport.
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 type SignUpRequest struct {
@@ -16,7 +18,46 @@ type SignUpRequest struct {
 	FullName string `json:"full_name" validate:"required"`
 }
 
+// SignUpResponse holds the issued tokens. They are omitted when the client asked for the cookie
+// token transport.
 type SignUpResponse struct {
-	AccessToken  string `json:"access_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// RefreshTokenRequest holds the refresh token to exchange. Clients using the cookie token transport
+// leave it empty and send the refresh token cookie instead.
+type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
+
+// RefreshTokenResponse holds the new tokens. They are omitted when the client asked for the cookie
+// token transport.
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type RevokeSuspiciousLoginRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type RequestPasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest holds the token of a password reset email and the new password.
+type ResetPasswordRequest struct {
+	Token       string `json:"token"        validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// TokenResponse holds the tokens issued by the v2 login, sign up and refresh routes, along with the
+// lifetime of the access token in seconds. The tokens are omitted when the client asked for the
+// cookie token transport.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
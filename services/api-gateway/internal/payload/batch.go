@@ -0,0 +1,22 @@
+package payload
+
+import "encoding/json"
+
+type BatchRequest struct {
+	Requests []BatchSubRequest `json:"requests" validate:"required,min=1,dive"`
+}
+
+type BatchSubRequest struct {
+	ID      string            `json:"id"      validate:"required,max=64"`
+	Method  string            `json:"method"  validate:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path    string            `json:"path"    validate:"required,startswith=/,max=2048"`
+	Headers map[string]string `json:"headers" validate:"dive,keys,oneof=Idempotency-Key If-None-Match,endkeys,max=256"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type BatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
@@ -0,0 +1,11 @@
+package payload
+
+type SubscribeEventsRequest struct {
+	LastEventID string `query:"last_event_id" validate:"omitempty,objectid"`
+}
+
+type EventResponse struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	Data any    `json:"data,omitempty"`
+}
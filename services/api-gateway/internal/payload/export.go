@@ -0,0 +1,16 @@
+package payload
+
+import "time"
+
+type ExportJobRequest struct {
+	ID string `params:"id" validate:"required,hexadecimal,len=24"`
+}
+
+type ExportJobResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,8 @@
+package payload
+
+// GraphQLRequest is a GraphQL request. Its fields are named as in the GraphQL over HTTP spec.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
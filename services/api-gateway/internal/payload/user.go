@@ -0,0 +1,47 @@
+package payload
+
+import "time"
+
+type ListSecurityEventsRequest struct {
+	Limit  uint64 `query:"limit"  validate:"omitempty,max=100"`
+	Cursor string `query:"cursor" validate:"omitempty,max=512"`
+}
+
+type SecurityEventResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	SessionID string    `json:"session_id,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserResponse struct {
+	ID              string    `json:"id"`
+	Email           string    `json:"email"`
+	FullName        string    `json:"full_name"`
+	Verified        bool      `json:"verified"`
+	DisplayName     string    `json:"display_name"`
+	AvatarURL       string    `json:"avatar_url"`
+	Locale          string    `json:"locale"`
+	Timezone        string    `json:"timezone"`
+	DefaultCurrency string    `json:"default_currency"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type UpdateProfileRequest struct {
+	FullName        *string `json:"full_name"        validate:"omitempty,min=1,max=100"`
+	DisplayName     *string `json:"display_name"     validate:"omitempty,max=50"`
+	AvatarURL       *string `json:"avatar_url"       validate:"omitempty,url"`
+	Locale          *string `json:"locale"           validate:"omitempty,bcp47_language_tag"`
+	Timezone        *string `json:"timezone"         validate:"omitempty,timezone"`
+	DefaultCurrency *string `json:"default_currency" validate:"omitempty,iso4217"`
+}
+
+// ChangePasswordRequest holds the user's current password, which they must confirm, and the new one.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password"     validate:"required"`
+}
@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"strconv"
+	"time"
+)
+
+// cacheControlNoStore is the Cache-Control of routes without a cache policy, whose responses may
+// hold tokens or change state and must not be stored.
+const cacheControlNoStore = "no-store"
+
+// Cache is the caching policy of a read route. Successful responses carry a strong ETag, and
+// requests whose If-None-Match holds it receive 304 Not Modified without a body.
+type Cache struct {
+	// MaxAge is how long a response may be reused without asking the gateway. With the zero max
+	// age, clients revalidate the response with its ETag on every use.
+	MaxAge time.Duration
+	// Public lets shared caches, such as CDNs, store the responses. Responses of routes that
+	// return the user's own data must stay private.
+	Public bool
+}
+
+func (p *Cache) header() string {
+	directive := "private"
+	if p.Public {
+		directive = "public"
+	}
+	if p.MaxAge <= 0 {
+		return directive + ", no-cache"
+	}
+
+	return directive + ", max-age=" + strconv.Itoa(int(p.MaxAge.Seconds()))
+}
@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+)
+
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+
+	unknownClient = "unknown"
+	otherClient   = "other"
+)
+
+// appClient matches the User-Agent products of the Moneylog apps, e.g. "MoneylogiOS/2.3.1", and
+// captures the app and its major version. Only two digits are kept, so that the client label of
+// the deprecated request metric has a bounded set of values.
+var appClient = regexp.MustCompile(`^(Moneylog(?:iOS|Android|Web))/(\d{1,2})(?:\.|$)`)
+
+// Deprecation marks routes that clients should stop calling. Responses of deprecated routes carry
+// the Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and requests to them are counted by
+// client in the http_deprecated_requests_total metric.
+type Deprecation struct {
+	// Date is when the routes were deprecated.
+	Date time.Time
+	// Sunset is when the routes will stop being served, or the zero time when it is not decided yet.
+	Sunset time.Time
+	// Successor is the path prefix that serves the routes' replacement, e.g. "/v1", if any.
+	Successor string
+}
+
+// handler wraps the handler of a deprecated route.
+func (d *Deprecation) handler(prefix string, handle fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(HeaderDeprecation, "@"+strconv.FormatInt(d.Date.Unix(), 10))
+		if !d.Sunset.IsZero() {
+			c.Set(HeaderSunset, d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Successor != "" {
+			successor := d.Successor + strings.TrimPrefix(c.Path(), prefix)
+			c.Append(fiber.HeaderLink, "<"+successor+`>; rel="successor-version"`)
+		}
+
+		metrics.RecordDeprecatedRequest(c.Method(), c.Route().Path, client(c))
+
+		return handle(c)
+	}
+}
+
+// client identifies the calling application by its app and major version, e.g. "MoneylogiOS/2"
+// for "MoneylogiOS/2.3.1". Requests without a User-Agent are from an unknown client, and the
+// User-Agents of other applications are counted together as other.
+func client(c *fiber.Ctx) string {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if userAgent == "" {
+		return unknownClient
+	}
+
+	match := appClient.FindStringSubmatch(userAgent)
+	if match == nil {
+		return otherClient
+	}
+
+	return match[1] + "/" + match[2]
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler is an HTTP endpoint served by a Fiber handler of its own rather than a gRPC method, such
+// as a stream or an endpoint that fans out to other routes. It is registered in the table so that
+// it is documented with the other routes. Req is the payload the handler reads and Resp the data of
+// its successful responses, or Empty when it returns none.
+type Handler[Req, Resp any] struct {
+	method      string
+	path        string
+	name        string
+	summary     string
+	status      int
+	handler     fiber.Handler
+	contentType string
+	deprecation *Deprecation
+}
+
+// NewHandler creates an endpoint that serves method and path with handler. The name identifies the
+// operation in the documentation, like the name of a gRPC request message.
+func NewHandler[Req, Resp any](method, path, name string, handler fiber.Handler) *Handler[Req, Resp] {
+	return &Handler[Req, Resp]{
+		method:  method,
+		path:    path,
+		name:    name,
+		status:  http.StatusOK,
+		handler: handler,
+	}
+}
+
+// WithSummary sets the summary of the operation, for names that do not read well as a sentence.
+func (h *Handler[Req, Resp]) WithSummary(summary string) *Handler[Req, Resp] {
+	h.summary = summary
+	return h
+}
+
+// WithContentType documents that the handler writes successful responses of the content type
+// instead of the response envelope.
+func (h *Handler[Req, Resp]) WithContentType(contentType string) *Handler[Req, Resp] {
+	h.contentType = contentType
+	return h
+}
+
+// Deprecated marks the endpoint as deprecated.
+func (h *Handler[Req, Resp]) Deprecated(d Deprecation) *Handler[Req, Resp] {
+	h.deprecation = &d
+	return h
+}
+
+func (h *Handler[Req, Resp]) operation(prefix string) Operation {
+	op := Operation{
+		Method:      h.method,
+		Path:        prefix + h.path,
+		Name:        h.name,
+		Summary:     h.summary,
+		Status:      h.status,
+		Request:     reflect.TypeFor[Req](),
+		ContentType: h.contentType,
+		Deprecation: h.deprecation,
+	}
+	if t := reflect.TypeFor[Resp](); t != reflect.TypeFor[Empty]() {
+		op.Response = t
+	}
+
+	return op
+}
+
+func (h *Handler[Req, Resp]) handle(c *fiber.Ctx) error {
+	return h.handler(c)
+}
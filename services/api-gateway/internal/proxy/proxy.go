@@ -0,0 +1,262 @@
+// Package proxy builds Fiber routes that forward requests to unary gRPC methods from a declarative
+// route table, so that handlers only describe how payloads map to and from gRPC messages.
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/response"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/validator"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// CallFunc invokes a unary gRPC method. Methods of generated clients can be used directly.
+type CallFunc[In, Out proto.Message] func(ctx context.Context, in In, opts ...grpc.CallOption) (Out, error)
+
+// RequestFunc builds the gRPC request from the decoded and validated payload.
+type RequestFunc[Req any, In proto.Message] func(c *fiber.Ctx, req *Req) In
+
+// ResponseFunc builds the response data from the gRPC response.
+type ResponseFunc[Out proto.Message, Resp any] func(c *fiber.Ctx, out Out) Resp
+
+// WriteFunc writes the gRPC response to the client itself, for routes that do not return the
+// JSON response envelope.
+type WriteFunc[Out proto.Message] func(c *fiber.Ctx, out Out) error
+
+// PageInfoFunc returns where the page in the gRPC response of a paginated route ends.
+type PageInfoFunc[Out proto.Message] func(out Out) contract.PageInfo
+
+// VersionFunc returns the version of the resource in the gRPC response, such as its update time.
+// It must change whenever the response does.
+type VersionFunc[Out proto.Message] func(out Out) string
+
+// SuccessFunc runs after the gRPC method succeeded and before the response is written, for routes
+// with side effects on the response such as cookies.
+type SuccessFunc[Out proto.Message] func(c *fiber.Ctx, out Out)
+
+// Empty is the payload of routes that read nothing from the request, and the response data of
+// routes that return none.
+type Empty struct{}
+
+// Route is an HTTP endpoint served by a unary gRPC method. A request is handled by decoding the
+// path parameters, query string and JSON body into the payload, validating it, building the gRPC
+// request, calling the method and wrapping the mapped response in the response envelope. gRPC
+// errors are translated with contract.NewGRPCErrorResponse.
+type Route[Req any, In, Out proto.Message, Resp any] struct {
+	method      string
+	path        string
+	status      int
+	call        CallFunc[In, Out]
+	request     RequestFunc[Req, In]
+	response    ResponseFunc[Out, Resp]
+	write       WriteFunc[Out]
+	pageInfo    PageInfoFunc[Out]
+	version     VersionFunc[Out]
+	success     SuccessFunc[Out]
+	contentType string
+	deprecation *Deprecation
+	cache       *Cache
+}
+
+// NewRoute creates a route that serves method and path with the gRPC method and returns the
+// mapped response as the envelope's data.
+func NewRoute[Req any, In, Out proto.Message, Resp any](
+	method, path string,
+	call CallFunc[In, Out],
+	request RequestFunc[Req, In],
+	response ResponseFunc[Out, Resp],
+) *Route[Req, In, Out, Resp] {
+	return &Route[Req, In, Out, Resp]{
+		method:   method,
+		path:     path,
+		status:   http.StatusOK,
+		call:     call,
+		request:  request,
+		response: response,
+	}
+}
+
+// NewCommand creates a route that serves method and path with the gRPC method and returns an
+// envelope without data.
+func NewCommand[Req any, In, Out proto.Message](
+	method, path string,
+	call CallFunc[In, Out],
+	request RequestFunc[Req, In],
+) *Route[Req, In, Out, Empty] {
+	return NewRoute[Req, In, Out, Empty](method, path, call, request, nil)
+}
+
+// WithStatus sets the HTTP status of successful responses.
+func (r *Route[Req, In, Out, Resp]) WithStatus(status int) *Route[Req, In, Out, Resp] {
+	r.status = status
+	return r
+}
+
+// WithWriter makes the route write successful responses of the content type with write instead
+// of the response envelope.
+func (r *Route[Req, In, Out, Resp]) WithWriter(contentType string, write WriteFunc[Out]) *Route[Req, In, Out, Resp] {
+	r.contentType = contentType
+	r.write = write
+	return r
+}
+
+// WithPageInfo makes the route return the page info of the gRPC response in the envelope, for
+// routes that list items a page at a time.
+func (r *Route[Req, In, Out, Resp]) WithPageInfo(pageInfo PageInfoFunc[Out]) *Route[Req, In, Out, Resp] {
+	r.pageInfo = pageInfo
+	return r
+}
+
+// WithCache lets clients cache the successful responses of the route with the policy, and
+// revalidate them with their ETag. Routes without a policy are sent with Cache-Control: no-store.
+func (r *Route[Req, In, Out, Resp]) WithCache(cache Cache) *Route[Req, In, Out, Resp] {
+	r.cache = &cache
+	return r
+}
+
+// WithVersion makes the ETag of a cached route depend on the version of the resource instead of
+// the response's content, so that unchanged resources are not serialized to be compared.
+func (r *Route[Req, In, Out, Resp]) WithVersion(version VersionFunc[Out]) *Route[Req, In, Out, Resp] {
+	r.version = version
+	return r
+}
+
+// WithSuccess makes the route run success after every successful gRPC call.
+func (r *Route[Req, In, Out, Resp]) WithSuccess(success SuccessFunc[Out]) *Route[Req, In, Out, Resp] {
+	r.success = success
+	return r
+}
+
+// Deprecated marks the route as deprecated.
+func (r *Route[Req, In, Out, Resp]) Deprecated(d Deprecation) *Route[Req, In, Out, Resp] {
+	r.deprecation = &d
+	return r
+}
+
+func (r *Route[Req, In, Out, Resp]) operation(prefix string) Operation {
+	var in In
+	op := Operation{
+		Method:      r.method,
+		Path:        prefix + r.path,
+		Name:        string(in.ProtoReflect().Descriptor().Name()),
+		Status:      r.status,
+		Request:     reflect.TypeFor[Req](),
+		ContentType: r.contentType,
+		Deprecation: r.deprecation,
+		Cache:       r.cache,
+	}
+	if r.response != nil {
+		op.Response = reflect.TypeFor[Resp]()
+	}
+
+	return op
+}
+
+func (r *Route[Req, In, Out, Resp]) handle(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, cacheControlNoStore)
+
+	var req Req
+	if err := decode(c, &req); err != nil {
+		return response.JSON(
+			c,
+			http.StatusBadRequest,
+			contract.NewErrorResponse(contract.ErrorCodeValidation, err.Error()),
+		)
+	}
+
+	if errs := validator.ValidateStruct(req, i18n.Locale(c)); len(errs) != 0 {
+		return response.JSON(c, http.StatusBadRequest, contract.NewValidationErrorResponse(errs))
+	}
+
+	out, err := r.call(c.UserContext(), r.request(c, &req))
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error().
+			Err(err).
+			Str("method", r.method).
+			Str("route", c.Route().Path).
+			Msg("Failed to call gRPC method")
+
+		return response.GRPCError(c, err)
+	}
+
+	if r.success != nil {
+		r.success(c, out)
+	}
+
+	if r.write != nil {
+		return r.write(c, out)
+	}
+
+	if r.cache != nil {
+		return r.writeCached(c, out)
+	}
+
+	return response.JSON(c, r.status, r.envelope(c, out))
+}
+
+// writeCached writes the response of a cached route with its cache policy and ETag, or 304 Not
+// Modified without a body when the request's If-None-Match already holds the ETag.
+func (r *Route[Req, In, Out, Resp]) writeCached(c *fiber.Ctx, out Out) error {
+	c.Set(fiber.HeaderCacheControl, r.cache.header())
+
+	var resp *contract.APIResponse
+	var etag string
+	if r.version != nil {
+		etag = contract.NewETag([]byte(r.version(out)))
+	} else {
+		envelope := r.envelope(c, out)
+		tag, err := envelope.ETag()
+		if err != nil {
+			return err
+		}
+		resp, etag = &envelope, tag
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+	if response.ETagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	if resp == nil {
+		envelope := r.envelope(c, out)
+		resp = &envelope
+	}
+
+	return response.JSON(c, r.status, *resp)
+}
+
+// envelope wraps the mapped gRPC response in the response envelope.
+func (r *Route[Req, In, Out, Resp]) envelope(c *fiber.Ctx, out Out) contract.APIResponse {
+	var data any
+	if r.response != nil {
+		data = r.response(c, out)
+	}
+
+	if r.pageInfo != nil {
+		return contract.NewPageResponse(data, r.pageInfo(out))
+	}
+
+	return contract.NewSuccessResponse(data)
+}
+
+// decode fills the payload from the path parameters, the query string and, when present, the body.
+func decode(c *fiber.Ctx, req any) error {
+	if err := c.ParamsParser(req); err != nil {
+		return err
+	}
+	if err := c.QueryParser(req); err != nil {
+		return err
+	}
+	if len(c.Body()) > 0 {
+		return c.BodyParser(req)
+	}
+
+	return nil
+}
@@ -0,0 +1,399 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/proxy"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type echoRequest struct {
+	ID      string `params:"id"`
+	Message string `json:"message" validate:"required"`
+	Repeat  int    `query:"repeat"`
+}
+
+func echo(_ context.Context, in *wrapperspb.StringValue, _ ...grpc.CallOption) (*wrapperspb.StringValue, error) {
+	if in.GetValue() == "fail" {
+		return nil, contract.NewGRPCError(codes.NotFound, contract.ErrorCodeNotFound, "nothing to echo")
+	}
+
+	return wrapperspb.String(strings.ToUpper(in.GetValue())), nil
+}
+
+func toEchoRequest(_ *fiber.Ctx, req *echoRequest) *wrapperspb.StringValue {
+	return wrapperspb.String(strings.Repeat(req.ID+":"+req.Message, max(req.Repeat, 1)))
+}
+
+func toEchoResponse(_ *fiber.Ctx, out *wrapperspb.StringValue) string {
+	return out.GetValue()
+}
+
+func newApp() *fiber.App {
+	app := fiber.New()
+	proxy.NewTable().Register(app,
+		proxy.NewRoute(fiber.MethodPost, "/echo/:id", echo, toEchoRequest, toEchoResponse).
+			WithStatus(http.StatusCreated),
+		proxy.NewCommand(fiber.MethodPost, "/fail", echo,
+			func(_ *fiber.Ctx, _ *proxy.Empty) *wrapperspb.StringValue { return wrapperspb.String("fail") },
+		),
+	)
+
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, path, body string) (int, contract.APIResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp contract.APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	return resp.StatusCode, apiResp
+}
+
+func TestRoute(t *testing.T) {
+	app := newApp()
+
+	t.Run("decodes params, query and body and maps the response", func(t *testing.T) {
+		status, resp := do(t, app, "/echo/a?repeat=2", `{"message":"hi"}`)
+		if status != http.StatusCreated {
+			t.Fatalf("status is %d, want %d", status, http.StatusCreated)
+		}
+		if resp.Data != "A:HIA:HI" {
+			t.Fatalf("data is %v, want %q", resp.Data, "A:HIA:HI")
+		}
+	})
+
+	t.Run("rejects invalid payloads before calling the method", func(t *testing.T) {
+		status, resp := do(t, app, "/echo/a", `{}`)
+		if status != http.StatusBadRequest {
+			t.Fatalf("status is %d, want %d", status, http.StatusBadRequest)
+		}
+		if resp.Error == nil || resp.Error.Code != contract.ErrorCodeValidation || len(resp.Error.Details) != 1 {
+			t.Fatalf("error is %+v, want one validation error", resp.Error)
+		}
+	})
+
+	t.Run("translates gRPC errors", func(t *testing.T) {
+		status, resp := do(t, app, "/fail", "")
+		if status != http.StatusNotFound {
+			t.Fatalf("status is %d, want %d", status, http.StatusNotFound)
+		}
+		if resp.Error == nil || resp.Error.Message != "nothing to echo" {
+			t.Fatalf("error is %+v, want the service message", resp.Error)
+		}
+	})
+}
+
+func TestDeprecated(t *testing.T) {
+	deprecation := proxy.Deprecation{
+		Date:      time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/v1",
+	}
+
+	app := fiber.New()
+	table := proxy.NewTable()
+	table.Deprecated(deprecation).Register(app,
+		proxy.NewRoute(fiber.MethodPost, "/echo/:id", echo, toEchoRequest, toEchoResponse),
+	)
+	table.Register(app.Group("/v1"),
+		proxy.NewRoute(fiber.MethodPost, "/echo/:id", echo, toEchoRequest, toEchoResponse),
+	)
+
+	tests := []struct {
+		name        string
+		path        string
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{
+			name:        "deprecated route",
+			path:        "/echo/a",
+			deprecation: "@1767225600",
+			sunset:      "Wed, 01 Jul 2026 00:00:00 GMT",
+			link:        `</v1/echo/a>; rel="successor-version"`,
+		},
+		{name: "current route", path: "/v1/echo/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"message":"hi"}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status is %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if got := resp.Header.Get(proxy.HeaderDeprecation); got != tt.deprecation {
+				t.Errorf("Deprecation is %q, want %q", got, tt.deprecation)
+			}
+			if got := resp.Header.Get(proxy.HeaderSunset); got != tt.sunset {
+				t.Errorf("Sunset is %q, want %q", got, tt.sunset)
+			}
+			if got := resp.Header.Get(fiber.HeaderLink); got != tt.link {
+				t.Errorf("Link is %q, want %q", got, tt.link)
+			}
+		})
+	}
+
+	for _, op := range table.Operations() {
+		if want := op.Path == "/echo/:id"; (op.Deprecation != nil) != want {
+			t.Errorf("operation %s deprecated = %v, want %v", op.Path, op.Deprecation != nil, want)
+		}
+	}
+}
+
+func TestDeprecatedClients(t *testing.T) {
+	app := fiber.New()
+	proxy.NewTable().Deprecated(proxy.Deprecation{Date: time.Now()}).Register(app,
+		proxy.NewRoute(fiber.MethodPost, "/clients/:id", echo, toEchoRequest, toEchoResponse),
+	)
+
+	for _, userAgent := range []string{
+		"MoneylogiOS/2.3.1 CFNetwork/1490",
+		"MoneylogiOS/2.4.0",
+		"MoneylogAndroid/10.0",
+		"MoneylogWeb/1",
+		"MoneylogiOS/123.0",
+		"MoneylogDesktop/1.0",
+		"curl/8.7.1",
+		"Mozilla/5.0 (X11; Linux x86_64)",
+		"",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/clients/a", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderUserAgent, userAgent)
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	got := map[string]float64{}
+	for _, family := range families {
+		if !strings.HasSuffix(family.GetName(), "_http_deprecated_requests_total") {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["route"] == "/clients/:id" {
+				got[labels["client"]] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"MoneylogiOS/2":      2,
+		"MoneylogAndroid/10": 1,
+		"MoneylogWeb/1":      1,
+		"other":              4,
+		"unknown":            1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("requests are counted by client as %v, want %v", got, want)
+	}
+	for client, n := range want {
+		if got[client] != n {
+			t.Errorf("requests of client %q are counted %v times, want %v", client, got[client], n)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	calls := 0
+	version := func(out *wrapperspb.StringValue) string {
+		calls++
+		return out.GetValue()
+	}
+
+	app := fiber.New()
+	proxy.NewTable().Register(app,
+		proxy.NewRoute(fiber.MethodGet, "/echo/:id", echo, toEchoRequest, toEchoResponse).
+			WithCache(proxy.Cache{MaxAge: time.Minute}),
+		proxy.NewRoute(fiber.MethodGet, "/versioned/:id", echo, toEchoRequest, toEchoResponse).
+			WithCache(proxy.Cache{}).
+			WithVersion(version),
+		proxy.NewRoute(fiber.MethodPost, "/echo/:id", echo, toEchoRequest, toEchoResponse),
+	)
+
+	get := func(t *testing.T, path, ifNoneMatch string) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	t.Run("tags equal content with the same ETag", func(t *testing.T) {
+		first := get(t, "/echo/a?message=hi", "")
+		time.Sleep(time.Millisecond)
+		second := get(t, "/echo/a?message=hi", "")
+		other := get(t, "/echo/b?message=hi", "")
+
+		etag := first.Header.Get(fiber.HeaderETag)
+		if etag == "" || second.Header.Get(fiber.HeaderETag) != etag {
+			t.Fatalf("ETags are %q and %q, want the same one", etag, second.Header.Get(fiber.HeaderETag))
+		}
+		if other.Header.Get(fiber.HeaderETag) == etag {
+			t.Fatalf("ETag of other content is %q too", etag)
+		}
+		if got := first.Header.Get(fiber.HeaderCacheControl); got != "private, max-age=60" {
+			t.Fatalf("Cache-Control is %q, want %q", got, "private, max-age=60")
+		}
+	})
+
+	t.Run("returns 304 when If-None-Match holds the ETag", func(t *testing.T) {
+		etag := get(t, "/echo/a?message=hi", "").Header.Get(fiber.HeaderETag)
+
+		resp := get(t, "/echo/a?message=hi", `"other", W/`+etag)
+		if resp.StatusCode != http.StatusNotModified {
+			t.Fatalf("status is %d, want %d", resp.StatusCode, http.StatusNotModified)
+		}
+		if resp.Header.Get(fiber.HeaderETag) != etag {
+			t.Fatalf("ETag is %q, want %q", resp.Header.Get(fiber.HeaderETag), etag)
+		}
+	})
+
+	t.Run("tags versioned routes by the version", func(t *testing.T) {
+		resp := get(t, "/versioned/a?message=hi", "")
+		if got := resp.Header.Get(fiber.HeaderCacheControl); got != "private, no-cache" {
+			t.Fatalf("Cache-Control is %q, want %q", got, "private, no-cache")
+		}
+
+		resp = get(t, "/versioned/a?message=hi", resp.Header.Get(fiber.HeaderETag))
+		if resp.StatusCode != http.StatusNotModified || calls != 2 {
+			t.Fatalf("status is %d after %d versions, want %d after 2", resp.StatusCode, calls, http.StatusNotModified)
+		}
+	})
+
+	t.Run("does not let routes without a policy be stored", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/echo/a", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if got := resp.Header.Get(fiber.HeaderCacheControl); got != "no-store" {
+			t.Fatalf("Cache-Control is %q, want %q", got, "no-store")
+		}
+		if got := resp.Header.Get(fiber.HeaderETag); got != "" {
+			t.Fatalf("ETag is %q, want none", got)
+		}
+	})
+}
+
+func TestTableLookup(t *testing.T) {
+	table := proxy.NewTable()
+	table.Register(fiber.New().Group("/v1/echo"),
+		proxy.NewRoute(fiber.MethodGet, "/", echo, toEchoRequest, toEchoResponse),
+		proxy.NewRoute(fiber.MethodPost, "/:id", echo, toEchoRequest, toEchoResponse),
+	)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: fiber.MethodGet, path: "/v1/echo", want: "/v1/echo/"},
+		{method: fiber.MethodGet, path: "/v1/echo/?repeat=2", want: "/v1/echo/"},
+		{method: fiber.MethodPost, path: "/v1/echo/a", want: "/v1/echo/:id"},
+		{method: fiber.MethodGet, path: "/v1/echo/a"},
+		{method: fiber.MethodPost, path: "/v1/echo/a/b"},
+		{method: fiber.MethodPost, path: "//v1/echo/a"},
+	}
+
+	for _, tt := range tests {
+		op, ok := table.Lookup(tt.method, tt.path)
+		if ok != (tt.want != "") || op.Path != tt.want {
+			t.Errorf("Lookup(%s %s) is %q, %v, want %q", tt.method, tt.path, op.Path, ok, tt.want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	app := fiber.New()
+	table := proxy.NewTable()
+	table.RegisterAuthenticated(app.Group("/v1/me"),
+		proxy.NewHandler[echoRequest, proxy.Empty](fiber.MethodGet, "/stream", "Stream", func(c *fiber.Ctx) error {
+			return c.SendString("streamed")
+		}).WithContentType("text/event-stream"),
+		proxy.NewHandler[echoRequest, []string](fiber.MethodPost, "/echoes", "Echoes", func(c *fiber.Ctx) error {
+			return c.JSON(contract.NewSuccessResponse([]string{"echo"}))
+		}).WithSummary("Echo many"),
+	)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/me/stream", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status is %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	ops := table.Operations()
+	if len(ops) != 2 {
+		t.Fatalf("table has %d operations, want 2", len(ops))
+	}
+
+	stream, echoes := ops[0], ops[1]
+	if stream.Path != "/v1/me/stream" || stream.Name != "Stream" || !stream.Authenticated {
+		t.Errorf("stream is documented as %+v", stream)
+	}
+	if stream.ContentType != "text/event-stream" || stream.Response != nil {
+		t.Errorf("stream is documented with %q and %v, want the content type without data", stream.ContentType,
+			stream.Response)
+	}
+	if echoes.Summary != "Echo many" || echoes.Response == nil || echoes.ContentType != "" {
+		t.Errorf("echoes is documented as %+v, want its summary and data in the envelope", echoes)
+	}
+}
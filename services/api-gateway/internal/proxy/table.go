@@ -0,0 +1,176 @@
+package proxy
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operation describes a registered route for API documentation.
+type Operation struct {
+	Method string
+	// Path is the full Fiber route path, including the prefix of the group it was registered on.
+	Path string
+	// Version is the API version of the route, e.g. "v1", or empty for unversioned routes.
+	Version string
+	// Name is the name of the gRPC request message, or of the operation for routes served by a
+	// handler of their own.
+	Name string
+	// Summary describes the operation when its name does not read well as a sentence.
+	Summary string
+	Status  int
+	// Authenticated reports whether the route was registered on a router that requires an access token.
+	Authenticated bool
+	Request       reflect.Type
+	// Response is the type of the envelope's data, or nil when the route returns no data.
+	Response reflect.Type
+	// ContentType is set when the route writes its own response instead of the envelope.
+	ContentType string
+	// Deprecation is set when the route is deprecated.
+	Deprecation *Deprecation
+	// Cache is set when clients may cache the route's responses and revalidate them with an ETag.
+	Cache *Cache
+}
+
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// Endpoint is a route that can be added to a Fiber router.
+type Endpoint interface {
+	operation(prefix string) Operation
+	handle(c *fiber.Ctx) error
+}
+
+// Table registers routes on Fiber routers and keeps a description of each for documentation.
+type Table struct {
+	registry    *registry
+	deprecation *Deprecation
+}
+
+type registry struct {
+	mu         sync.Mutex
+	operations []Operation
+}
+
+// NewTable creates an empty route table.
+func NewTable() *Table {
+	return &Table{registry: &registry{}}
+}
+
+// Deprecated returns a view of the table that marks the routes registered through it as deprecated,
+// unless they carry a deprecation of their own. The routes are recorded in the same table.
+func (t *Table) Deprecated(d Deprecation) *Table {
+	return &Table{registry: t.registry, deprecation: &d}
+}
+
+// Register adds the routes to the router.
+func (t *Table) Register(router fiber.Router, endpoints ...Endpoint) {
+	t.register(router, false, endpoints)
+}
+
+// RegisterAuthenticated adds the routes to a router whose middleware requires an access token, so
+// that they are documented as authenticated. The table does not enforce authentication itself.
+func (t *Table) RegisterAuthenticated(router fiber.Router, endpoints ...Endpoint) {
+	t.register(router, true, endpoints)
+}
+
+// Operations returns the registered routes in registration order.
+func (t *Table) Operations() []Operation {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+
+	return append([]Operation(nil), t.registry.operations...)
+}
+
+// Lookup returns the registered route that serves the method and path. The path may carry a query
+// string, which is ignored.
+func (t *Table) Lookup(method, path string) (Operation, bool) {
+	path, _, _ = strings.Cut(path, "?")
+	segments := pathSegments(path)
+
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+
+	for _, op := range t.registry.operations {
+		if op.Method == method && matchPath(op.Path, segments) {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
+
+func (t *Table) register(router fiber.Router, authenticated bool, endpoints []Endpoint) {
+	var prefix string
+	if group, ok := router.(*fiber.Group); ok {
+		prefix = group.Prefix
+	}
+
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+
+	for _, endpoint := range endpoints {
+		op := endpoint.operation(prefix)
+		op.Authenticated = authenticated
+		op.Version = apiVersion(prefix)
+		if op.Deprecation == nil {
+			op.Deprecation = t.deprecation
+		}
+
+		handle := endpoint.handle
+		if op.Deprecation != nil {
+			handle = op.Deprecation.handler(versionPrefix(op.Version), handle)
+		}
+
+		router.Add(op.Method, op.Path[len(prefix):], handle)
+		t.registry.operations = append(t.registry.operations, op)
+	}
+}
+
+// matchPath reports whether the path segments match a Fiber route path, whose ":name" segments
+// match any non-empty segment.
+func matchPath(pattern string, segments []string) bool {
+	parts := pathSegments(pattern)
+	if len(parts) != len(segments) {
+		return false
+	}
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pathSegments splits a path into its segments, ignoring a trailing slash as Fiber does.
+func pathSegments(path string) []string {
+	return strings.Split(strings.TrimPrefix(strings.TrimSuffix(path, "/"), "/"), "/")
+}
+
+// apiVersion returns the API version a route prefix starts with, e.g. "v1" for "/v1/me".
+func apiVersion(prefix string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(prefix, "/"), "/")
+	if !versionSegment.MatchString(segment) {
+		return ""
+	}
+
+	return segment
+}
+
+func versionPrefix(version string) string {
+	if version == "" {
+		return ""
+	}
+
+	return "/" + version
+}
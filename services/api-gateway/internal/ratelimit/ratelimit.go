@@ -0,0 +1,55 @@
+// Package ratelimit implements token bucket rate limiting with pluggable counter stores.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit describes a token bucket that holds up to Burst tokens and refills completely every Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// rate returns the number of tokens added to the bucket per millisecond.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / float64(l.Period.Milliseconds())
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps token bucket state. Take must atomically refill the bucket for key and
+// consume a single token when one is available.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult builds a Result from the number of tokens left in a bucket after a take.
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	rate := limit.rate()
+
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: millis((float64(limit.Burst) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = millis((1 - tokens) / rate)
+	}
+
+	return result
+}
+
+func millis(ms float64) time.Duration {
+	return time.Duration(math.Ceil(ms)) * time.Millisecond
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a token bucket stored in a Redis hash in a single atomic step.
+// KEYS[1] is the bucket key; ARGV holds the burst, the period and the current time in milliseconds.
+var takeScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(state[1])
+local updated_at = tonumber(state[2])
+if tokens == nil or updated_at == nil then
+	tokens = burst
+	updated_at = now
+end
+
+local elapsed = math.max(0, now - updated_at)
+tokens = math.min(burst, tokens + elapsed * burst / period)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated_at", tostring(now))
+redis.call("PEXPIRE", KEYS[1], period)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps token buckets in Redis, or any server speaking the Redis protocol with Lua
+// scripting, so that limits are shared by every gateway instance.
+type RedisStore struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+// NewRedisStore creates a new Redis backed token bucket store.
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeScript.Run(
+		ctx,
+		s.client,
+		[]string{key},
+		limit.Burst,
+		limit.Period.Milliseconds(),
+		s.now().UnixMilli(),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(allowed == 1, tokens, limit), nil
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/vasapolrittideah/moneylog-api/shared/clocktest"
+)
+
+func newMemoryStore(_ *testing.T, clock *clocktest.Clock) Store {
+	store := NewMemoryStore()
+	store.now = clock.Now
+
+	return store
+}
+
+func newRedisStore(t *testing.T, clock *clocktest.Clock) Store {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := NewRedisStore(client)
+	store.now = clock.Now
+
+	return store
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(*testing.T, *clocktest.Clock) Store{
+		"memory": newMemoryStore,
+		"redis":  newRedisStore,
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Run("allows up to the burst then rejects", func(t *testing.T) {
+				clock := clocktest.New()
+				store := newStore(t, clock)
+				limit := Limit{Burst: 3, Period: 3 * time.Second}
+
+				for i := range 3 {
+					result := mustTake(t, store, "client", limit)
+					if !result.Allowed {
+						t.Fatalf("request %d was rejected", i+1)
+					}
+					if result.Remaining != 2-i {
+						t.Fatalf("request %d: remaining = %d, want %d", i+1, result.Remaining, 2-i)
+					}
+				}
+
+				result := mustTake(t, store, "client", limit)
+				if result.Allowed {
+					t.Fatal("request over the burst was allowed")
+				}
+				if result.RetryAfter != time.Second {
+					t.Fatalf("retry after = %v, want %v", result.RetryAfter, time.Second)
+				}
+				if result.ResetAfter != 3*time.Second {
+					t.Fatalf("reset after = %v, want %v", result.ResetAfter, 3*time.Second)
+				}
+			})
+
+			t.Run("refills over time", func(t *testing.T) {
+				clock := clocktest.New()
+				store := newStore(t, clock)
+				limit := Limit{Burst: 2, Period: 2 * time.Second}
+
+				mustTake(t, store, "client", limit)
+				mustTake(t, store, "client", limit)
+				if mustTake(t, store, "client", limit).Allowed {
+					t.Fatal("request over the burst was allowed")
+				}
+
+				clock.Advance(time.Second)
+				if !mustTake(t, store, "client", limit).Allowed {
+					t.Fatal("request after refill was rejected")
+				}
+			})
+
+			t.Run("keeps separate buckets per key", func(t *testing.T) {
+				clock := clocktest.New()
+				store := newStore(t, clock)
+				limit := Limit{Burst: 1, Period: time.Minute}
+
+				if !mustTake(t, store, "a", limit).Allowed {
+					t.Fatal("first request for a was rejected")
+				}
+				if !mustTake(t, store, "b", limit).Allowed {
+					t.Fatal("first request for b was rejected")
+				}
+				if mustTake(t, store, "a", limit).Allowed {
+					t.Fatal("second request for a was allowed")
+				}
+			})
+		})
+	}
+}
+
+func mustTake(t *testing.T, store Store, key string, limit Limit) Result {
+	t.Helper()
+
+	result, err := store.Take(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+
+	return result
+}
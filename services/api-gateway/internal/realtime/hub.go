@@ -0,0 +1,221 @@
+// Package realtime relays the domain events of the backend services to the connections of the
+// users they belong to. It is independent of the transport: the HTTP handlers write the events to
+// WebSocket or Server-Sent Events connections.
+package realtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxReconnectDelay bounds the delay before a broken backend stream is reopened.
+const maxReconnectDelay = 30 * time.Second
+
+// ErrTooManyConnections is returned when a user already has the maximum number of connections.
+var ErrTooManyConnections = errors.New("too many connections")
+
+// SubscribeFunc opens a backend stream of a user's events. The SubscribeEvents method of the
+// generated client can be used directly.
+type SubscribeFunc func(
+	ctx context.Context,
+	in *authpbv1.SubscribeEventsRequest,
+	opts ...grpc.CallOption,
+) (grpc.ServerStreamingClient[authpbv1.Event], error)
+
+// Sink is a client connection that events are written to.
+type Sink interface {
+	Send(event *authpbv1.Event) error
+	// Heartbeat tells the client that the connection is alive while there are no events.
+	Heartbeat() error
+}
+
+// Options tunes a hub. MaxConnectionsPerUser is the number of connections a user may have open.
+// ReconnectDelay is the first delay before a broken backend stream is reopened; it doubles on
+// every failed attempt.
+type Options struct {
+	HeartbeatInterval     time.Duration
+	MaxConnectionsPerUser int
+	ReconnectDelay        time.Duration
+}
+
+// Hub relays events from the backend to client connections and limits the connections per user.
+type Hub struct {
+	subscribe SubscribeFunc
+	opts      Options
+
+	mu          sync.Mutex
+	connections map[string]int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewHub creates a hub that opens backend streams with subscribe.
+func NewHub(subscribe SubscribeFunc, opts Options) *Hub {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Hub{
+		subscribe:   subscribe,
+		opts:        opts,
+		connections: make(map[string]int),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Connect reserves one of the user's connections. It returns ErrTooManyConnections when the user
+// has none left; otherwise release must be called when the connection closes.
+func (h *Hub) Connect(userID string) (func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.connections[userID] >= h.opts.MaxConnectionsPerUser {
+		return nil, ErrTooManyConnections
+	}
+	h.connections[userID]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			if h.connections[userID]--; h.connections[userID] <= 0 {
+				delete(h.connections, userID)
+			}
+		})
+	}, nil
+}
+
+// Stream writes the user's events recorded after lastEventID to the sink, and heartbeats while
+// there are none, until ctx is done, the hub is closed or writing fails. When the backend stream
+// breaks, it is reopened after the last event written, so the client misses no events.
+func (h *Hub) Stream(ctx context.Context, userID, lastEventID string, sink Sink) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(h.ctx, cancel)
+	defer stop()
+
+	heartbeat := time.NewTicker(h.opts.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	delay := h.opts.ReconnectDelay
+	for {
+		err := h.relay(ctx, userID, &lastEventID, heartbeat, sink, &delay)
+		var sinkErr *sinkError
+		if errors.As(err, &sinkErr) {
+			return sinkErr.err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status.Code(err) == codes.InvalidArgument {
+			return err
+		}
+
+		logger.FromContext(ctx).Warn().Err(err).Dur("retryIn", delay).Msg("Event stream broke, reconnecting")
+		if err := h.wait(ctx, delay, heartbeat, sink); err != nil {
+			return err
+		}
+		delay = min(2*delay, maxReconnectDelay)
+	}
+}
+
+// Close ends every stream, so that the server can shut down without waiting for the clients to
+// disconnect.
+func (h *Hub) Close() {
+	h.cancel()
+}
+
+// sinkError wraps the errors of the sink, which end the stream instead of reopening it.
+type sinkError struct {
+	err error
+}
+
+func (e *sinkError) Error() string {
+	return e.err.Error()
+}
+
+// relay opens a backend stream and writes its events to the sink until the stream or the sink
+// fails. The reconnect delay is reset once an event is received, rather than when the stream
+// opens, since a backend that rejects the stream does so on the first receive.
+func (h *Hub) relay(
+	ctx context.Context,
+	userID string,
+	lastEventID *string,
+	heartbeat *time.Ticker,
+	sink Sink,
+	delay *time.Duration,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := h.subscribe(ctx, &authpbv1.SubscribeEventsRequest{UserId: userID, LastEventId: *lastEventID})
+	if err != nil {
+		return err
+	}
+
+	received := make(chan *authpbv1.Event)
+	failed := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				failed <- err
+				return
+			}
+			select {
+			case received <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-failed:
+			return err
+		case event := <-received:
+			*delay = h.opts.ReconnectDelay
+			if err := sink.Send(event); err != nil {
+				return &sinkError{err: err}
+			}
+			*lastEventID = event.GetId()
+			heartbeat.Reset(h.opts.HeartbeatInterval)
+		case <-heartbeat.C:
+			if err := sink.Heartbeat(); err != nil {
+				return &sinkError{err: err}
+			}
+		}
+	}
+}
+
+// wait waits for the delay, keeping the client connection alive with heartbeats.
+func (h *Hub) wait(ctx context.Context, delay time.Duration, heartbeat *time.Ticker, sink Sink) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			return nil
+		case <-heartbeat.C:
+			if err := sink.Heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package realtime_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/realtime"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream returns its events and then err, or blocks until ctx is done when err is nil.
+type fakeStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []*authpbv1.Event
+	err    error
+}
+
+func (s *fakeStream) Recv() (*authpbv1.Event, error) {
+	if len(s.events) > 0 {
+		event := s.events[0]
+		s.events = s.events[1:]
+		return event, nil
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	<-s.ctx.Done()
+
+	return nil, status.FromContextError(s.ctx.Err()).Err()
+}
+
+// fakeSink records the IDs of the events sent to it and calls onSend after each one.
+type fakeSink struct {
+	mu           sync.Mutex
+	ids          []string
+	onSend       func(ids []string)
+	heartbeatErr error
+}
+
+func (s *fakeSink) Send(event *authpbv1.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids = append(s.ids, event.GetId())
+	if s.onSend != nil {
+		s.onSend(s.ids)
+	}
+
+	return nil
+}
+
+func (s *fakeSink) Heartbeat() error {
+	return s.heartbeatErr
+}
+
+func TestHubConnect(t *testing.T) {
+	hub := realtime.NewHub(nil, realtime.Options{MaxConnectionsPerUser: 1})
+	defer hub.Close()
+
+	release, err := hub.Connect("user")
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if _, err := hub.Connect("user"); !errors.Is(err, realtime.ErrTooManyConnections) {
+		t.Fatalf("second Connect error is %v, want ErrTooManyConnections", err)
+	}
+	if _, err := hub.Connect("other"); err != nil {
+		t.Fatalf("Connect of another user: %v", err)
+	}
+
+	release()
+	release()
+	if _, err := hub.Connect("user"); err != nil {
+		t.Fatalf("Connect after release: %v", err)
+	}
+	if _, err := hub.Connect("user"); !errors.Is(err, realtime.ErrTooManyConnections) {
+		t.Fatalf("Connect error is %v, want ErrTooManyConnections after releasing twice", err)
+	}
+}
+
+func TestHubStream(t *testing.T) {
+	t.Run("reopens a broken stream after the last event written", func(t *testing.T) {
+		var requests []*authpbv1.SubscribeEventsRequest
+		subscribe := func(
+			ctx context.Context,
+			in *authpbv1.SubscribeEventsRequest,
+			_ ...grpc.CallOption,
+		) (grpc.ServerStreamingClient[authpbv1.Event], error) {
+			requests = append(requests, in)
+			if len(requests) == 1 {
+				return &fakeStream{
+					ctx:    ctx,
+					events: []*authpbv1.Event{{Id: "1"}, {Id: "2"}},
+					err:    status.Error(codes.Unavailable, "connection reset"),
+				}, nil
+			}
+			return &fakeStream{ctx: ctx, events: []*authpbv1.Event{{Id: "3"}}}, nil
+		}
+		hub := realtime.NewHub(subscribe, realtime.Options{
+			HeartbeatInterval: time.Hour,
+			ReconnectDelay:    time.Millisecond,
+		})
+		defer hub.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := &fakeSink{onSend: func(ids []string) {
+			if len(ids) == 3 {
+				cancel()
+			}
+		}}
+
+		if err := hub.Stream(ctx, "user", "0", sink); err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		if len(requests) != 2 || requests[0].GetLastEventId() != "0" || requests[1].GetLastEventId() != "2" {
+			t.Fatalf("requests are %v, want last event IDs 0 and 2", requests)
+		}
+		if len(sink.ids) != 3 {
+			t.Fatalf("sent events %v, want 1, 2 and 3", sink.ids)
+		}
+	})
+
+	t.Run("ends when the client cannot be written to", func(t *testing.T) {
+		subscribe := func(
+			ctx context.Context,
+			_ *authpbv1.SubscribeEventsRequest,
+			_ ...grpc.CallOption,
+		) (grpc.ServerStreamingClient[authpbv1.Event], error) {
+			return &fakeStream{ctx: ctx}, nil
+		}
+		hub := realtime.NewHub(subscribe, realtime.Options{HeartbeatInterval: time.Millisecond})
+		defer hub.Close()
+
+		errClosed := errors.New("connection closed")
+		err := hub.Stream(context.Background(), "user", "", &fakeSink{heartbeatErr: errClosed})
+		if !errors.Is(err, errClosed) {
+			t.Fatalf("Stream error is %v, want the heartbeat error", err)
+		}
+	})
+
+	t.Run("does not retry rejected requests", func(t *testing.T) {
+		calls := 0
+		subscribe := func(
+			context.Context,
+			*authpbv1.SubscribeEventsRequest,
+			...grpc.CallOption,
+		) (grpc.ServerStreamingClient[authpbv1.Event], error) {
+			calls++
+			return nil, status.Error(codes.InvalidArgument, "invalid last event ID")
+		}
+		hub := realtime.NewHub(subscribe, realtime.Options{HeartbeatInterval: time.Hour})
+		defer hub.Close()
+
+		err := hub.Stream(context.Background(), "user", "bad", &fakeSink{})
+		if status.Code(err) != codes.InvalidArgument || calls != 1 {
+			t.Fatalf("Stream error is %v after %d calls, want InvalidArgument after 1", err, calls)
+		}
+	})
+
+	t.Run("backs off while streams fail before receiving an event", func(t *testing.T) {
+		const delay = 10 * time.Millisecond
+
+		var opened []time.Time
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		subscribe := func(
+			ctx context.Context,
+			_ *authpbv1.SubscribeEventsRequest,
+			_ ...grpc.CallOption,
+		) (grpc.ServerStreamingClient[authpbv1.Event], error) {
+			if opened = append(opened, time.Now()); len(opened) == 4 {
+				cancel()
+			}
+			return &fakeStream{ctx: ctx, err: status.Error(codes.Unavailable, "connection refused")}, nil
+		}
+		hub := realtime.NewHub(subscribe, realtime.Options{HeartbeatInterval: time.Hour, ReconnectDelay: delay})
+		defer hub.Close()
+
+		if err := hub.Stream(ctx, "user", "", &fakeSink{}); err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		for i := 1; i < len(opened); i++ {
+			want := delay << (i - 1)
+			if got := opened[i].Sub(opened[i-1]); got < want {
+				t.Errorf("stream %d was reopened after %v, want at least %v", i+1, got, want)
+			}
+		}
+	})
+}
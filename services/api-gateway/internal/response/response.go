@@ -0,0 +1,81 @@
+// Package response writes contract.APIResponse envelopes to the client.
+package response
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+)
+
+// JSON writes the response envelope with the given status, stamping it with the request ID. Error
+// messages are translated into the locale of the request.
+func JSON(c *fiber.Ctx, status int, resp contract.APIResponse) error {
+	resp.RequestID = requestid.FromContext(c.UserContext())
+
+	if resp.Error != nil {
+		locale := i18n.Locale(c)
+		resp.Error.Message = i18n.Translate(locale, resp.Error.Message)
+		for i := range resp.Error.Details {
+			resp.Error.Details[i].Message = i18n.Translate(locale, resp.Error.Details[i].Message)
+		}
+
+		c.Set(fiber.HeaderContentLanguage, locale)
+		c.Vary(fiber.HeaderAcceptLanguage)
+	}
+
+	return c.Status(status).JSON(resp)
+}
+
+// ETagMatches reports whether an If-None-Match header value holds the entity tag. Tags are compared
+// weakly, as RFC 9110 requires for If-None-Match.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	for tag := range strings.SplitSeq(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GRPCError translates an error returned by a gRPC service into an error response, setting
+// Retry-After when the service asked the client to retry later.
+func GRPCError(c *fiber.Ctx, err error) error {
+	resp := contract.NewGRPCErrorResponse(err)
+	if seconds := resp.RetryAfterSeconds(); seconds > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	}
+
+	return JSON(c, resp.Status, resp.Response)
+}
+
+// ErrorHandler is the app's fiber.ErrorHandler. It writes errors that handlers did not turn into a
+// response themselves, such as unknown routes or bodies over the app's body limit, as error envelopes.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) {
+		return JSON(c, http.StatusInternalServerError, contract.NewErrorResponse(
+			contract.ErrorCodeInternal,
+			"internal server error",
+		))
+	}
+
+	code := contract.ErrorCodeInternal
+	switch {
+	case fiberErr.Code == http.StatusNotFound:
+		code = contract.ErrorCodeNotFound
+	case fiberErr.Code == http.StatusRequestEntityTooLarge:
+		code = contract.ErrorCodePayloadTooLarge
+	case fiberErr.Code < http.StatusInternalServerError:
+		code = contract.ErrorCodeBadRequest
+	}
+
+	return JSON(c, fiberErr.Code, contract.NewErrorResponse(code, fiberErr.Message))
+}
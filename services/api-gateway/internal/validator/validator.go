@@ -6,30 +6,45 @@ import (
 	"strings"
 
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/th"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	enTrans "github.com/go-playground/validator/v10/translations/en"
+	thTrans "github.com/go-playground/validator/v10/translations/th"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
 	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	sharedvalidator "github.com/vasapolrittideah/moneylog-api/shared/validator"
 )
 
+// defaultTranslations registers the go-playground translations of a locale. A locale without
+// defaults only has the messages of its catalog.
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	i18n.English: enTrans.RegisterDefaultTranslations,
+	i18n.Thai:    thTrans.RegisterDefaultTranslations,
+}
+
 var (
-	val   = validator.New()
-	trans = registerTranslation()
+	val         = newValidate()
+	translators = registerTranslations()
 )
 
-func ValidateStruct(input any) []contract.APIValidationError {
+// ValidateStruct validates the input and returns its validation errors with messages in the locale.
+func ValidateStruct(input any, locale string) []contract.APIValidationError {
 	var errs []contract.APIValidationError
 	if err := val.Struct(input); err != nil {
 		var validationErrors validator.ValidationErrors
 		if errors.As(err, &validationErrors) {
-			errs = translateErrorMessage(validationErrors)
+			errs = translateErrorMessage(validationErrors, translator(locale))
 		}
 	}
 
 	return errs
 }
 
-func translateErrorMessage(validationErrors validator.ValidationErrors) []contract.APIValidationError {
+func translateErrorMessage(
+	validationErrors validator.ValidationErrors,
+	trans ut.Translator,
+) []contract.APIValidationError {
 	var errs []contract.APIValidationError
 	var invalidField contract.APIValidationError
 	for _, err := range validationErrors {
@@ -45,11 +60,38 @@ func translateErrorMessage(validationErrors validator.ValidationErrors) []contra
 	return errs
 }
 
-func registerTranslation() ut.Translator {
+func newValidate() *validator.Validate {
+	v := validator.New()
+	_ = sharedvalidator.RegisterValidations(v)
+
+	return v
+}
+
+func translator(locale string) ut.Translator {
+	if trans, ok := translators[locale]; ok {
+		return trans
+	}
+
+	return translators[i18n.DefaultLocale]
+}
+
+// registerTranslations registers the default translations and the catalog messages of every
+// supported locale, and returns the translator of each.
+func registerTranslations() map[string]ut.Translator {
 	english := en.New()
-	universalTranslator := ut.New(english, english)
-	trans, _ := universalTranslator.GetTranslator("en")
-	_ = enTrans.RegisterDefaultTranslations(val, trans)
+	universalTranslator := ut.New(english, english, th.New())
+
+	translators := make(map[string]ut.Translator)
+	for _, locale := range i18n.Locales() {
+		trans, _ := universalTranslator.GetTranslator(locale)
+		if register, ok := defaultTranslations[locale]; ok {
+			_ = register(val, trans)
+		}
+		for tag, message := range i18n.ValidationMessages(locale) {
+			_ = val.RegisterTranslation(tag, trans, registrationFunc(tag, message), translateFunc)
+		}
+		translators[locale] = trans
+	}
 
 	val.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		const jsonTagParts = 2
@@ -60,5 +102,20 @@ func registerTranslation() ut.Translator {
 		return name
 	})
 
-	return trans
+	return translators
+}
+
+func registrationFunc(tag, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateFunc(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field(), fe.Param())
+	if err != nil {
+		return fe.Error()
+	}
+
+	return message
 }
@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/i18n"
+	"github.com/vasapolrittideah/moneylog-api/services/api-gateway/internal/validator"
+)
+
+type moneyPayload struct {
+	Amount    string `json:"amount"     validate:"decimal=2,positive_amount"`
+	Balance   string `json:"balance"    validate:"decimal=2"`
+	Currency  string `json:"currency"   validate:"iso4217"`
+	Timezone  string `json:"timezone"   validate:"timezone"`
+	StartDate string `json:"start_date" validate:"date"`
+	EndDate   string `json:"end_date"   validate:"date,date_gtefield=StartDate"`
+	AccountID string `json:"account_id" validate:"objectid"`
+	Color     string `json:"color"      validate:"hex_color"`
+}
+
+func TestValidateStructTranslatesCustomTags(t *testing.T) {
+	invalid := moneyPayload{
+		Amount:    "-1.00",
+		Balance:   "1.005",
+		Currency:  "XYZ",
+		Timezone:  "Mars/Olympus",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-01-01",
+		AccountID: "123",
+		Color:     "red",
+	}
+
+	for _, locale := range i18n.Locales() {
+		t.Run(locale, func(t *testing.T) {
+			errs := validator.ValidateStruct(invalid, locale)
+			if len(errs) != 7 {
+				t.Fatalf("got %d errors, want 7: %+v", len(errs), errs)
+			}
+
+			for _, err := range errs {
+				// Untranslated errors fall back to the validator's "Key: ... Error:Field validation" text.
+				if strings.Contains(err.Message, "Error:Field validation") {
+					t.Errorf("%s: message is not translated: %q", err.Field, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStructAcceptsValidMoney(t *testing.T) {
+	valid := moneyPayload{
+		Amount:    "1250.50",
+		Balance:   "-30",
+		Currency:  "THB",
+		Timezone:  "Asia/Bangkok",
+		StartDate: "2026-01-01",
+		EndDate:   "2026-01-31",
+		AccountID: "6566f1b2c3d4e5f6a7b8c9d0",
+		Color:     "#FF8800",
+	}
+
+	if errs := validator.ValidateStruct(valid, i18n.DefaultLocale); len(errs) != 0 {
+		t.Errorf("ValidateStruct() = %+v, want no errors", errs)
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/config"
 	grpchandler "github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/delivery/grpc"
@@ -14,12 +17,27 @@ import (
 	"github.com/vasapolrittideah/moneylog-api/shared/auth"
 	"github.com/vasapolrittideah/moneylog-api/shared/database"
 	"github.com/vasapolrittideah/moneylog-api/shared/discovery"
+	"github.com/vasapolrittideah/moneylog-api/shared/export"
+	"github.com/vasapolrittideah/moneylog-api/shared/healthcheck"
 	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/mail"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+	"github.com/vasapolrittideah/moneylog-api/shared/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
+const (
+	activeSessionsInterval = 30 * time.Second
+	eventPollInterval      = time.Second
+	healthCheckInterval    = 10 * time.Second
+	healthCheckTimeout     = 3 * time.Second
+	shutdownTimeout        = 10 * time.Second
+)
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -27,6 +45,17 @@ func main() {
 
 	authServiceCfg := config.NewAuthServiceConfig(logger)
 	mongoCfg := database.NewMongoConfig(logger)
+	tracingCfg := tracing.NewTracingConfig(logger)
+
+	tracerProvider, err := tracing.NewTracerProvider(ctx, authServiceCfg.Name, tracingCfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create tracer provider")
+	}
+	defer func() {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("Failed to shutdown tracer provider")
+		}
+	}()
 
 	mongoDB := database.NewMongoDB(mongoCfg, logger)
 	if err := mongoDB.Connect(ctx); err != nil {
@@ -62,8 +91,39 @@ func main() {
 	identityRepo := mongodb.NewIdentityRepository(ctx, logger, mongoDB.GetDatabase())
 	sessionRepo := mongodb.NewSessionRepository(ctx, logger, mongoDB.GetDatabase())
 	userRepo := mongodb.NewUserRepository(ctx, logger, mongoDB.GetDatabase())
+	exportJobRepo := mongodb.NewExportJobRepository(ctx, logger, mongoDB.GetDatabase())
+	authEventRepo := mongodb.NewAuthEventRepository(ctx, logger, mongoDB.GetDatabase())
+	knownDeviceRepo := mongodb.NewKnownDeviceRepository(ctx, logger, mongoDB.GetDatabase())
+	loginAlertRepo := mongodb.NewLoginAlertRepository(ctx, logger, mongoDB.GetDatabase())
+	passwordResetRepo := mongodb.NewPasswordResetRepository(ctx, logger, mongoDB.GetDatabase())
+
+	smtpCfg := mail.NewSMTPConfig(logger)
+	mailer := mail.NewMailer(smtpCfg, logger)
+
+	exportRegistry := export.NewRegistry(
+		usecase.NewUserExportProvider(userRepo),
+		usecase.NewIdentityExportProvider(identityRepo),
+		usecase.NewSessionExportProvider(sessionRepo),
+		usecase.NewAuthEventExportProvider(authEventRepo),
+		usecase.NewKnownDeviceExportProvider(knownDeviceRepo),
+		usecase.NewLoginAlertExportProvider(loginAlertRepo),
+	)
 
-	authUsecase := usecase.NewAuthUsecase(identityRepo, sessionRepo, userRepo, jwtAuthenticator, authServiceCfg)
+	authUsecase := usecase.NewAuthUsecase(
+		identityRepo,
+		sessionRepo,
+		userRepo,
+		authEventRepo,
+		usecase.NewAuthEventBroker(ctx, authEventRepo, eventPollInterval),
+		knownDeviceRepo,
+		loginAlertRepo,
+		passwordResetRepo,
+		jwtAuthenticator,
+		mailer,
+		authServiceCfg,
+	)
+	userUsecase := usecase.NewUserUsecase(userRepo)
+	exportUsecase := usecase.NewExportUsecase(exportJobRepo, userRepo, exportRegistry)
 
 	lc := net.ListenConfig{}
 	lis, err := lc.Listen(ctx, "tcp", authServiceCfg.Addr)
@@ -71,13 +131,50 @@ func main() {
 		logger.Fatal().Err(err).Msg("Failed to listen on gRPC address")
 	}
 
-	grpcServer := grpc.NewServer()
-	grpchandler.NewAuthGRPCHandler(grpcServer, authUsecase)
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(logger),
+			metrics.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(logger),
+			metrics.StreamServerInterceptor(),
+		),
+	)
+	grpchandler.NewAuthGRPCHandler(grpcServer, authUsecase, userUsecase, exportUsecase)
 
+	// The service only reports SERVING once the health monitor has seen MongoDB and the Consul
+	// registration healthy. The registration is restored when Consul dropped it while MongoDB was
+	// down, so the service does not stay NOT_SERVING once MongoDB is back.
 	healthServer := health.NewServer()
-	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
+	healthMonitor := healthcheck.NewMonitor(healthServer, healthcheck.Checks{
+		"mongodb": mongoDB.Ping,
+		"consul": func(ctx context.Context) error {
+			return consulRegistry.EnsureRegistered(ctx, serviceID, authServiceCfg.Name, authServiceCfg.RegisterAddr)
+		},
+	}, healthCheckInterval, healthCheckTimeout, logger)
+	go healthMonitor.Run(ctx)
+
+	metricsCfg := metrics.NewMetricsConfig(logger)
+	metricsServer := metrics.NewServer(metricsCfg)
+	go func() {
+		logger.Info().Str("address", metricsCfg.Addr).Msg("Starting metrics server")
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Msg("Failed to start metrics server")
+		}
+	}()
+	defer func() {
+		if err := metricsServer.Shutdown(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("Failed to shutdown metrics server")
+		}
+	}()
+
+	go usecase.RecordActiveSessions(ctx, sessionRepo, activeSessionsInterval)
+
 	go func() {
 		signalChan := make(chan os.Signal, 1)
 		signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
@@ -95,5 +192,19 @@ func main() {
 
 	<-ctx.Done()
 	logger.Info().Msg("Shutting down gRPC server")
-	grpcServer.GracefulStop()
+	healthServer.Shutdown()
+
+	// The event broker ends the open SubscribeEvents streams with ctx; Stop cuts off any other call
+	// that outlives the timeout.
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		logger.Warn().Msg("Timed out waiting for gRPC calls to finish")
+		grpcServer.Stop()
+	}
 }
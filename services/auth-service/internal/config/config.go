@@ -13,6 +13,8 @@ type AuthServiceConfig struct {
 	Addr         string `env:"SERVICE_ADDR"`
 	RegisterAddr string `env:"SERVICE_REGISTER_ADDR"`
 	Token        TokenConfig
+	Notification NotificationConfig
+	Pagination   PaginationConfig
 }
 
 type TokenConfig struct {
@@ -23,6 +25,16 @@ type TokenConfig struct {
 	Issuer                string        `env:"TOKEN_ISSUER"`
 }
 
+type NotificationConfig struct {
+	LoginAlertURL    string `env:"LOGIN_ALERT_URL"`
+	PasswordResetURL string `env:"PASSWORD_RESET_URL"`
+}
+
+type PaginationConfig struct {
+	// CursorSecret signs the pagination cursors; without it clients could forge them.
+	CursorSecret string `env:"CURSOR_SECRET,required,notEmpty"`
+}
+
 func NewAuthServiceConfig(logger *zerolog.Logger) *AuthServiceConfig {
 	cfg, err := env.ParseAs[AuthServiceConfig]()
 	if err != nil {
@@ -6,21 +6,32 @@ import (
 
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/usecase"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
 	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type authGRPCHandler struct {
 	authpbv1.UnimplementedAuthServiceServer
 
-	authUsecase domain.AuthUsecase
+	authUsecase   domain.AuthUsecase
+	userUsecase   domain.UserUsecase
+	exportUsecase domain.ExportUsecase
 }
 
-func NewAuthGRPCHandler(server *grpc.Server, authUsecase domain.AuthUsecase) authpbv1.AuthServiceServer {
+func NewAuthGRPCHandler(
+	server *grpc.Server,
+	authUsecase domain.AuthUsecase,
+	userUsecase domain.UserUsecase,
+	exportUsecase domain.ExportUsecase,
+) authpbv1.AuthServiceServer {
 	handler := &authGRPCHandler{
-		authUsecase: authUsecase,
+		authUsecase:   authUsecase,
+		userUsecase:   userUsecase,
+		exportUsecase: exportUsecase,
 	}
 	authpbv1.RegisterAuthServiceServer(server, handler)
 
@@ -29,23 +40,32 @@ func NewAuthGRPCHandler(server *grpc.Server, authUsecase domain.AuthUsecase) aut
 
 func (h *authGRPCHandler) Login(ctx context.Context, req *authpbv1.LoginRequest) (*authpbv1.LoginResponse, error) {
 	params := domain.LoginParams{
-		Email:    req.GetEmail(),
-		Password: req.GetPassword(),
+		Email:     req.GetEmail(),
+		Password:  req.GetPassword(),
+		IPAddress: req.GetIpAddress(),
+		UserAgent: req.GetUserAgent(),
 	}
 
 	tokens, err := h.authUsecase.Login(ctx, params)
 	if err != nil {
-		var code codes.Code
 		switch {
 		case errors.Is(err, usecase.ErrInvalidCredentials):
-			code = codes.Unauthenticated
+			return nil, domainError(
+				codes.Unauthenticated,
+				contract.ErrorCodeInvalidCredentials,
+				usecase.ErrInvalidCredentials,
+			)
 		case errors.Is(err, usecase.ErrUserNotFound):
-			code = codes.NotFound
+			return nil, domainError(codes.NotFound, contract.ErrorCodeUserNotFound, usecase.ErrUserNotFound)
+		case errors.Is(err, usecase.ErrPasswordResetRequired):
+			return nil, domainError(
+				codes.FailedPrecondition,
+				contract.ErrorCodePasswordResetRequired,
+				usecase.ErrPasswordResetRequired,
+			)
 		default:
-			code = codes.Internal
+			return nil, internalError(ctx, err, "Failed to login")
 		}
-
-		return nil, status.Errorf(code, "failed to login: %v", err)
 	}
 
 	return &authpbv1.LoginResponse{
@@ -56,22 +76,25 @@ func (h *authGRPCHandler) Login(ctx context.Context, req *authpbv1.LoginRequest)
 
 func (h *authGRPCHandler) SignUp(ctx context.Context, req *authpbv1.SignUpRequest) (*authpbv1.SignUpResponse, error) {
 	params := domain.SignUpParams{
-		Email:    req.GetEmail(),
-		Password: req.GetPassword(),
-		FullName: req.GetFullName(),
+		Email:     req.GetEmail(),
+		Password:  req.GetPassword(),
+		FullName:  req.GetFullName(),
+		IPAddress: req.GetIpAddress(),
+		UserAgent: req.GetUserAgent(),
 	}
 
 	tokens, err := h.authUsecase.SignUp(ctx, params)
 	if err != nil {
-		var code codes.Code
 		switch {
 		case errors.Is(err, usecase.ErrUserAlreadyExists):
-			code = codes.AlreadyExists
+			return nil, domainError(
+				codes.AlreadyExists,
+				contract.ErrorCodeUserAlreadyExists,
+				usecase.ErrUserAlreadyExists,
+			)
 		default:
-			code = codes.Internal
+			return nil, internalError(ctx, err, "Failed to sign up")
 		}
-
-		return nil, status.Errorf(code, "failed to sign up: %v", err)
 	}
 
 	return &authpbv1.SignUpResponse{
@@ -79,3 +102,213 @@ func (h *authGRPCHandler) SignUp(ctx context.Context, req *authpbv1.SignUpReques
 		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
+
+func (h *authGRPCHandler) RefreshToken(
+	ctx context.Context,
+	req *authpbv1.RefreshTokenRequest,
+) (*authpbv1.RefreshTokenResponse, error) {
+	tokens, err := h.authUsecase.RefreshToken(ctx, domain.RefreshTokenParams{
+		RefreshToken: req.GetRefreshToken(),
+		IPAddress:    req.GetIpAddress(),
+		UserAgent:    req.GetUserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidRefreshToken):
+			return nil, domainError(
+				codes.Unauthenticated,
+				contract.ErrorCodeInvalidRefreshToken,
+				usecase.ErrInvalidRefreshToken,
+			)
+		default:
+			return nil, internalError(ctx, err, "Failed to refresh token")
+		}
+	}
+
+	return &authpbv1.RefreshTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (h *authGRPCHandler) ListSecurityEvents(
+	ctx context.Context,
+	req *authpbv1.ListSecurityEventsRequest,
+) (*authpbv1.ListSecurityEventsResponse, error) {
+	page, err := h.authUsecase.ListSecurityEvents(ctx, domain.ListSecurityEventsParams{
+		UserID: req.GetUserId(),
+		Limit:  req.GetLimit(),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		if errors.Is(err, contract.ErrInvalidCursor) {
+			return nil, domainError(codes.InvalidArgument, contract.ErrorCodeInvalidCursor, contract.ErrInvalidCursor)
+		}
+		return nil, internalError(ctx, err, "Failed to list security events")
+	}
+
+	pbEvents := make([]*authpbv1.SecurityEvent, 0, len(page.Events))
+	for _, event := range page.Events {
+		pbEvents = append(pbEvents, toSecurityEventProto(event))
+	}
+
+	return &authpbv1.ListSecurityEventsResponse{
+		Events:     pbEvents,
+		NextCursor: page.NextCursor,
+		HasMore:    page.NextCursor != "",
+	}, nil
+}
+
+func (h *authGRPCHandler) SubscribeEvents(
+	req *authpbv1.SubscribeEventsRequest,
+	stream grpc.ServerStreamingServer[authpbv1.Event],
+) error {
+	ctx := stream.Context()
+	params := domain.SubscribeEventsParams{
+		UserID:      req.GetUserId(),
+		LastEventID: req.GetLastEventId(),
+	}
+
+	err := h.authUsecase.SubscribeEvents(ctx, params, func(event domain.AuthEvent) error {
+		return stream.Send(&authpbv1.Event{
+			Id:      event.ID.Hex(),
+			Payload: &authpbv1.Event_SecurityEvent{SecurityEvent: toSecurityEventProto(event)},
+		})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidLastEventID):
+			return domainError(codes.InvalidArgument, contract.ErrorCodeBadRequest, usecase.ErrInvalidLastEventID)
+		case errors.Is(err, usecase.ErrSubscriberLagged):
+			return domainError(codes.Aborted, contract.ErrorCodeConflict, usecase.ErrSubscriberLagged)
+		case ctx.Err() != nil:
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return internalError(ctx, err, "Failed to stream events")
+	}
+
+	return nil
+}
+
+func (h *authGRPCHandler) RevokeSuspiciousLogin(
+	ctx context.Context,
+	req *authpbv1.RevokeSuspiciousLoginRequest,
+) (*authpbv1.RevokeSuspiciousLoginResponse, error) {
+	if err := h.authUsecase.RevokeSuspiciousLogin(ctx, req.GetToken()); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidLoginAlert):
+			return nil, domainError(
+				codes.InvalidArgument,
+				contract.ErrorCodeInvalidLoginAlert,
+				usecase.ErrInvalidLoginAlert,
+			)
+		default:
+			return nil, internalError(ctx, err, "Failed to revoke suspicious login")
+		}
+	}
+
+	return &authpbv1.RevokeSuspiciousLoginResponse{}, nil
+}
+
+func (h *authGRPCHandler) RequestPasswordReset(
+	ctx context.Context,
+	req *authpbv1.RequestPasswordResetRequest,
+) (*authpbv1.RequestPasswordResetResponse, error) {
+	if err := h.authUsecase.RequestPasswordReset(ctx, req.GetEmail()); err != nil {
+		return nil, internalError(ctx, err, "Failed to request password reset")
+	}
+
+	return &authpbv1.RequestPasswordResetResponse{}, nil
+}
+
+func (h *authGRPCHandler) ResetPassword(
+	ctx context.Context,
+	req *authpbv1.ResetPasswordRequest,
+) (*authpbv1.ResetPasswordResponse, error) {
+	if err := h.authUsecase.ResetPassword(ctx, domain.ResetPasswordParams{
+		Token:       req.GetToken(),
+		NewPassword: req.GetNewPassword(),
+	}); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPasswordReset):
+			return nil, domainError(
+				codes.InvalidArgument,
+				contract.ErrorCodeInvalidPasswordReset,
+				usecase.ErrInvalidPasswordReset,
+			)
+		default:
+			return nil, internalError(ctx, err, "Failed to reset password")
+		}
+	}
+
+	return &authpbv1.ResetPasswordResponse{}, nil
+}
+
+func (h *authGRPCHandler) Logout(ctx context.Context, req *authpbv1.LogoutRequest) (*authpbv1.LogoutResponse, error) {
+	if err := h.authUsecase.Logout(ctx, domain.LogoutParams{
+		UserID:    req.GetUserId(),
+		SessionID: req.GetSessionId(),
+		IPAddress: req.GetIpAddress(),
+		UserAgent: req.GetUserAgent(),
+	}); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrSessionNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeNotFound, usecase.ErrSessionNotFound)
+		default:
+			return nil, internalError(ctx, err, "Failed to logout")
+		}
+	}
+
+	return &authpbv1.LogoutResponse{}, nil
+}
+
+func (h *authGRPCHandler) ChangePassword(
+	ctx context.Context,
+	req *authpbv1.ChangePasswordRequest,
+) (*authpbv1.ChangePasswordResponse, error) {
+	if err := h.authUsecase.ChangePassword(ctx, domain.ChangePasswordParams{
+		UserID:          req.GetUserId(),
+		SessionID:       req.GetSessionId(),
+		CurrentPassword: req.GetCurrentPassword(),
+		NewPassword:     req.GetNewPassword(),
+		IPAddress:       req.GetIpAddress(),
+		UserAgent:       req.GetUserAgent(),
+	}); err != nil {
+		switch {
+		// A wrong current password is not reported as Unauthenticated, which clients take to mean
+		// that their access token has expired.
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			return nil, domainError(
+				codes.InvalidArgument,
+				contract.ErrorCodeInvalidCredentials,
+				usecase.ErrInvalidCredentials,
+			)
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeUserNotFound, usecase.ErrUserNotFound)
+		default:
+			return nil, internalError(ctx, err, "Failed to change password")
+		}
+	}
+
+	return &authpbv1.ChangePasswordResponse{}, nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func toSecurityEventProto(event domain.AuthEvent) *authpbv1.SecurityEvent {
+	return &authpbv1.SecurityEvent{
+		Id:        event.ID.Hex(),
+		Type:      string(event.Type),
+		SessionId: event.SessionID,
+		IpAddress: stringValue(event.IPAddress),
+		UserAgent: stringValue(event.UserAgent),
+		Reason:    event.Reason,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+}
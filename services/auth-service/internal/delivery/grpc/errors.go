@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// exportRetryDelay is how long clients are asked to wait before downloading an export that is not ready.
+const exportRetryDelay = 5 * time.Second
+
+// domainError creates a status error for an expected usecase error. The sentinel's own message is
+// returned to the caller, so sentinels must describe the problem without internal detail.
+func domainError(code codes.Code, reason string, sentinel error) error {
+	return contract.NewGRPCError(code, reason, sentinel.Error())
+}
+
+// internalError logs an unexpected error and returns an Internal status that does not expose it.
+func internalError(ctx context.Context, err error, msg string) error {
+	logger.FromContext(ctx).Error().Err(err).Msg(msg)
+	return contract.NewGRPCError(codes.Internal, contract.ErrorCodeInternal, "internal server error")
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/usecase"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *authGRPCHandler) ExportUserData(
+	ctx context.Context,
+	req *authpbv1.ExportUserDataRequest,
+) (*authpbv1.ExportUserDataResponse, error) {
+	job, err := h.exportUsecase.ExportUserData(ctx, req.GetUserId())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeUserNotFound, usecase.ErrUserNotFound)
+		default:
+			return nil, internalError(ctx, err, "Failed to export user data")
+		}
+	}
+
+	return &authpbv1.ExportUserDataResponse{
+		Job: toExportJobProto(job),
+	}, nil
+}
+
+func (h *authGRPCHandler) GetExportJob(
+	ctx context.Context,
+	req *authpbv1.GetExportJobRequest,
+) (*authpbv1.GetExportJobResponse, error) {
+	job, err := h.exportUsecase.GetExportJob(ctx, req.GetUserId(), req.GetJobId())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrExportJobNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeExportJobNotFound, usecase.ErrExportJobNotFound)
+		default:
+			return nil, internalError(ctx, err, "Failed to get export job")
+		}
+	}
+
+	return &authpbv1.GetExportJobResponse{
+		Job: toExportJobProto(job),
+	}, nil
+}
+
+func (h *authGRPCHandler) DownloadExport(
+	ctx context.Context,
+	req *authpbv1.DownloadExportRequest,
+) (*authpbv1.DownloadExportResponse, error) {
+	job, err := h.exportUsecase.DownloadExport(ctx, req.GetUserId(), req.GetJobId())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrExportJobNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeExportJobNotFound, usecase.ErrExportJobNotFound)
+		case errors.Is(err, usecase.ErrExportNotReady):
+			return nil, contract.NewGRPCError(
+				codes.FailedPrecondition,
+				contract.ErrorCodeExportNotReady,
+				usecase.ErrExportNotReady.Error(),
+				contract.NewRetryInfo(exportRetryDelay),
+			)
+		default:
+			return nil, internalError(ctx, err, "Failed to download export")
+		}
+	}
+
+	return &authpbv1.DownloadExportResponse{
+		FileName: "moneylog-export-" + job.ID.Hex() + ".json",
+		Archive:  job.Archive,
+	}, nil
+}
+
+func toExportJobProto(job *domain.ExportJob) *authpbv1.ExportJob {
+	pb := &authpbv1.ExportJob{
+		Id:        job.ID.Hex(),
+		UserId:    job.UserID,
+		Status:    string(job.Status),
+		Error:     job.Error,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}
+	if job.CompletedAt != nil {
+		pb.CompletedAt = timestamppb.New(*job.CompletedAt)
+	}
+
+	return pb
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/usecase"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *authGRPCHandler) GetMe(ctx context.Context, req *authpbv1.GetMeRequest) (*authpbv1.GetMeResponse, error) {
+	user, err := h.userUsecase.GetMe(ctx, req.GetUserId())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeUserNotFound, usecase.ErrUserNotFound)
+		default:
+			return nil, internalError(ctx, err, "Failed to get user")
+		}
+	}
+
+	return &authpbv1.GetMeResponse{
+		User: toUserProto(user),
+	}, nil
+}
+
+func (h *authGRPCHandler) UpdateProfile(
+	ctx context.Context,
+	req *authpbv1.UpdateProfileRequest,
+) (*authpbv1.UpdateProfileResponse, error) {
+	user, err := h.userUsecase.UpdateProfile(ctx, domain.UpdateProfileParams{
+		UserID:          req.GetUserId(),
+		FullName:        req.FullName,
+		DisplayName:     req.DisplayName,
+		AvatarURL:       req.AvatarUrl,
+		Locale:          req.Locale,
+		Timezone:        req.Timezone,
+		DefaultCurrency: req.DefaultCurrency,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, domainError(codes.NotFound, contract.ErrorCodeUserNotFound, usecase.ErrUserNotFound)
+		case errors.Is(err, usecase.ErrInvalidTimezone):
+			return nil, contract.NewGRPCError(
+				codes.InvalidArgument,
+				contract.ErrorCodeValidation,
+				"Validation failed",
+				contract.NewBadRequest(contract.NewFieldViolation("timezone", usecase.ErrInvalidTimezone.Error())),
+			)
+		default:
+			return nil, internalError(ctx, err, "Failed to update profile")
+		}
+	}
+
+	return &authpbv1.UpdateProfileResponse{
+		User: toUserProto(user),
+	}, nil
+}
+
+func toUserProto(user *domain.User) *authpbv1.User {
+	return &authpbv1.User{
+		Id:              user.ID.Hex(),
+		Email:           user.Email,
+		FullName:        user.FullName,
+		Verified:        user.Verified,
+		DisplayName:     user.DisplayName,
+		AvatarUrl:       user.AvatarURL,
+		Locale:          user.Locale,
+		Timezone:        user.Timezone,
+		DefaultCurrency: user.DefaultCurrency,
+		CreatedAt:       timestamppb.New(user.CreatedAt),
+		UpdatedAt:       timestamppb.New(user.UpdatedAt),
+	}
+}
@@ -10,17 +10,84 @@ import (
 type AuthUsecase interface {
 	Login(ctx context.Context, params LoginParams) (*authtypes.Tokens, error)
 	SignUp(ctx context.Context, params SignUpParams) (*authtypes.Tokens, error)
+	RefreshToken(ctx context.Context, params RefreshTokenParams) (*authtypes.Tokens, error)
+	ListSecurityEvents(ctx context.Context, params ListSecurityEventsParams) (*SecurityEventPage, error)
+	RevokeSuspiciousLogin(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, params ResetPasswordParams) error
+	Logout(ctx context.Context, params LogoutParams) error
+	ChangePassword(ctx context.Context, params ChangePasswordParams) error
+	SubscribeEvents(ctx context.Context, params SubscribeEventsParams, send func(AuthEvent) error) error
 }
 
 // LoginParams contains the parameters for user login.
 type LoginParams struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IPAddress string
+	UserAgent string
 }
 
 // SignUpParams contains the parameters for user sign up.
 type SignUpParams struct {
-	Email    string
-	Password string
-	FullName string
+	Email     string
+	Password  string
+	FullName  string
+	IPAddress string
+	UserAgent string
+}
+
+// RefreshTokenParams contains the parameters for exchanging a refresh token for new tokens.
+type RefreshTokenParams struct {
+	RefreshToken string
+	IPAddress    string
+	UserAgent    string
+}
+
+// ResetPasswordParams contains the parameters for resetting a password with the token of a
+// password reset email.
+type ResetPasswordParams struct {
+	Token       string
+	NewPassword string
+}
+
+// LogoutParams contains the parameters for signing out of the session an access token was issued
+// for.
+type LogoutParams struct {
+	UserID    string
+	SessionID string
+	IPAddress string
+	UserAgent string
+}
+
+// ChangePasswordParams contains the parameters for a signed in user changing their password.
+// SessionID is the session the change was made from, which stays signed in.
+type ChangePasswordParams struct {
+	UserID          string
+	SessionID       string
+	CurrentPassword string
+	NewPassword     string
+	IPAddress       string
+	UserAgent       string
+}
+
+// ListSecurityEventsParams contains the parameters for listing a user's security events.
+// Cursor is the token returned with the previous page.
+type ListSecurityEventsParams struct {
+	UserID string
+	Limit  uint64
+	Cursor string
+}
+
+// SubscribeEventsParams contains the parameters for streaming a user's security events. When
+// LastEventID is set, the events recorded after it are replayed first.
+type SubscribeEventsParams struct {
+	UserID      string
+	LastEventID string
+}
+
+// SecurityEventPage is a page of a user's security events. NextCursor is empty on the last page.
+type SecurityEventPage struct {
+	Events     []AuthEvent
+	NextCursor string
 }
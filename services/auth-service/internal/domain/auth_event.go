@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuthEventType identifies the kind of security-relevant action recorded in the audit log.
+type AuthEventType string
+
+const (
+	AuthEventSignUp          AuthEventType = "signup"
+	AuthEventLoginSucceeded  AuthEventType = "login_succeeded"
+	AuthEventLoginFailed     AuthEventType = "login_failed"
+	AuthEventTokenRefreshed  AuthEventType = "token_refreshed"
+	AuthEventLogout          AuthEventType = "logout"
+	AuthEventPasswordChanged AuthEventType = "password_changed"
+	AuthEventNewDeviceLogin  AuthEventType = "new_device_login"
+	AuthEventPasswordReset   AuthEventType = "password_reset"
+
+	AuthEventSuspiciousLoginReported AuthEventType = "suspicious_login_reported"
+)
+
+// AuthEvent represents an append-only audit record of an authentication event.
+// Events are never updated or deleted once written.
+type AuthEvent struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    string        `bson:"user_id,omitempty"`
+	Type      AuthEventType `bson:"type"`
+	Email     string        `bson:"email,omitempty"`
+	SessionID string        `bson:"session_id,omitempty"`
+	IPAddress *string       `bson:"ip_address"`
+	UserAgent *string       `bson:"user_agent"`
+	Reason    string        `bson:"reason,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// AuthEventRepository defines the interface for the append-only security audit log.
+type AuthEventRepository interface {
+	CreateAuthEvent(ctx context.Context, event *AuthEvent) (*AuthEvent, error)
+	ListAuthEventsByUserID(
+		ctx context.Context,
+		userID string,
+		params FilterAuthEventParams,
+	) ([]AuthEvent, *contract.Cursor, error)
+	// ListAuthEventsAfter returns up to limit of the user's events recorded after the event with
+	// afterID, oldest first.
+	ListAuthEventsAfter(ctx context.Context, userID, afterID string, limit uint64) ([]AuthEvent, error)
+	// ListAuthEventsSince returns a page of the events of every user recorded at or after since,
+	// oldest first. After is the cursor returned with the previous page.
+	ListAuthEventsSince(
+		ctx context.Context,
+		since time.Time,
+		limit uint64,
+		after *contract.Cursor,
+	) ([]AuthEvent, *contract.Cursor, error)
+}
+
+// AuthEventBroker delivers the auth events recorded by every instance of the service to the live
+// subscribers of this instance.
+type AuthEventBroker interface {
+	// Subscribe returns the channel the user's events are delivered on and a function that ends
+	// the subscription. The channel is closed when the subscriber falls behind.
+	Subscribe(userID string) (<-chan AuthEvent, func())
+	// Done is closed when the broker stops, at shutdown, which ends every subscription.
+	Done() <-chan struct{}
+}
+
+// FilterAuthEventParams contains the parameters for paginating audit log queries, newest first.
+// After is the cursor returned with the previous page.
+type FilterAuthEventParams struct {
+	Limit uint64
+	After *contract.Cursor
+}
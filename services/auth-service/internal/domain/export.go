@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ExportStatus represents the lifecycle state of a user data export job.
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob represents an asynchronous export of everything the system holds about a user.
+// The generated JSON archive is stored on the job once the export has completed.
+type ExportJob struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	UserID      string        `bson:"user_id"`
+	Status      ExportStatus  `bson:"status"`
+	Error       string        `bson:"error,omitempty"`
+	Archive     []byte        `bson:"archive,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	CompletedAt *time.Time    `bson:"completed_at,omitempty"`
+}
+
+// ExportJobRepository defines the interface for export job data persistence operations.
+type ExportJobRepository interface {
+	CreateExportJob(ctx context.Context, job *ExportJob) (*ExportJob, error)
+	GetExportJob(ctx context.Context, id string) (*ExportJob, error)
+	UpdateExportJob(ctx context.Context, id string, params UpdateExportJobParams) (*ExportJob, error)
+}
+
+// UpdateExportJobParams contains the optional parameters for updating an export job.
+// Only non-nil fields will be updated.
+type UpdateExportJobParams struct {
+	Status      *ExportStatus
+	Error       *string
+	Archive     []byte
+	CompletedAt *time.Time
+}
+
+// ExportUsecase defines the interface for user data export business logics.
+type ExportUsecase interface {
+	ExportUserData(ctx context.Context, userID string) (*ExportJob, error)
+	GetExportJob(ctx context.Context, userID string, jobID string) (*ExportJob, error)
+	DownloadExport(ctx context.Context, userID string, jobID string) (*ExportJob, error)
+}
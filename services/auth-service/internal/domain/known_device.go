@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// KnownDevice represents a device and network location a user has previously signed in from.
+// The fingerprint is derived from the user agent, so the same browser seen from a different
+// IP address is stored as a separate entry.
+type KnownDevice struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	UserID      string        `bson:"user_id"`
+	Fingerprint string        `bson:"fingerprint"`
+	IPAddress   string        `bson:"ip_address"`
+	UserAgent   string        `bson:"user_agent"`
+	FirstSeenAt time.Time     `bson:"first_seen_at"`
+	LastSeenAt  time.Time     `bson:"last_seen_at"`
+}
+
+// KnownDeviceRepository defines the interface for the per-user known device store.
+type KnownDeviceRepository interface {
+	GetKnownDevicesByUserID(ctx context.Context, userID string) ([]KnownDevice, error)
+	UpsertKnownDevice(ctx context.Context, device *KnownDevice) error
+}
+
+// LoginAlert represents a "wasn't you?" notification sent for a login from a new device.
+// Only the hash of the alert token is stored; the token itself is delivered in the email link.
+type LoginAlert struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    string        `bson:"user_id"`
+	SessionID string        `bson:"session_id"`
+	TokenHash string        `bson:"token_hash"`
+	ExpiresAt time.Time     `bson:"expires_at"`
+	UsedAt    *time.Time    `bson:"used_at,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// LoginAlertRepository defines the interface for login alert data persistence operations.
+type LoginAlertRepository interface {
+	CreateLoginAlert(ctx context.Context, alert *LoginAlert) (*LoginAlert, error)
+	GetLoginAlertByTokenHash(ctx context.Context, tokenHash string) (*LoginAlert, error)
+	MarkLoginAlertUsed(ctx context.Context, id string) error
+	ListLoginAlertsByUserID(ctx context.Context, userID string) ([]LoginAlert, error)
+}
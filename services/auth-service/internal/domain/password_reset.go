@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PasswordReset represents a request to reset a user's password. Only the hash of the reset token
+// is stored; the token itself is delivered in the email link.
+type PasswordReset struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	UserID    string        `bson:"user_id"`
+	TokenHash string        `bson:"token_hash"`
+	ExpiresAt time.Time     `bson:"expires_at"`
+	UsedAt    *time.Time    `bson:"used_at,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// PasswordResetRepository defines the interface for password reset data persistence operations.
+type PasswordResetRepository interface {
+	CreatePasswordReset(ctx context.Context, reset *PasswordReset) (*PasswordReset, error)
+	GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*PasswordReset, error)
+	// MarkPasswordResetUsed marks an unused reset as used. It returns mongo.ErrNoDocuments when the
+	// reset has already been used, so that a token resets the password at most once.
+	MarkPasswordResetUsed(ctx context.Context, id string) error
+}
@@ -4,36 +4,44 @@ import (
 	"context"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // Session represents an authenticated user session with access and refresh tokens.
 // It tracks token expiration times and optional metadata like IP address and user agent
 // for security and auditing purposes.
 type Session struct {
-	ID                    primitive.ObjectID `bson:"_id,omitempty"`
-	UserID                string             `bson:"user_id"`
-	AccessToken           string             `bson:"access_token"`
-	RefreshToken          string             `bson:"refresh_token"`
-	AccessTokenExpiresAt  time.Time          `bson:"access_token_expires_at"`
-	RefreshTokenExpiresAt time.Time          `bson:"refresh_token_expires_at"`
-	IPAddress             *string            `bson:"ip_address"`
-	UserAgent             *string            `bson:"user_agent"`
-	CreatedAt             time.Time          `bson:"created_at"`
-	UpdatedAt             time.Time          `bson:"updated_at"`
+	ID                    bson.ObjectID `bson:"_id,omitempty"`
+	UserID                string        `bson:"user_id"`
+	AccessToken           string        `bson:"access_token"`
+	RefreshToken          string        `bson:"refresh_token"`
+	AccessTokenExpiresAt  time.Time     `bson:"access_token_expires_at"`
+	RefreshTokenExpiresAt time.Time     `bson:"refresh_token_expires_at"`
+	IPAddress             *string       `bson:"ip_address"`
+	UserAgent             *string       `bson:"user_agent"`
+	RevokedAt             *time.Time    `bson:"revoked_at,omitempty"`
+	CreatedAt             time.Time     `bson:"created_at"`
+	UpdatedAt             time.Time     `bson:"updated_at"`
 }
 
 // SessionRepository defines the interface for session data persistence operations.
 type SessionRepository interface {
 	CreateSession(ctx context.Context, session *Session) (*Session, error)
+	GetSession(ctx context.Context, id string) (*Session, error)
 	GetSessionByUserID(ctx context.Context, userID string) (*Session, error)
+	ListSessionsByUserID(ctx context.Context, userID string) ([]Session, error)
 	UpdateTokens(ctx context.Context, userID string, params UpdateTokensParams) (*Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	CountActiveSessions(ctx context.Context) (int64, error)
 }
 
-// UpdateTokensParams contains the parameters for updating session tokens.
+// UpdateTokensParams contains the parameters for updating session tokens. When
+// PreviousRefreshToken is set, the tokens are only swapped while the session still holds it, and
+// mongo.ErrNoDocuments is returned otherwise, so that a refresh token is exchanged at most once.
 type UpdateTokensParams struct {
 	AccessToken           string    `bson:"access_token"`
 	RefreshToken          string    `bson:"refresh_token"`
 	AccessTokenExpiresAt  time.Time `bson:"access_token_expires_at"`
 	RefreshTokenExpiresAt time.Time `bson:"refresh_token_expires_at"`
+	PreviousRefreshToken  string    `bson:"-"`
 }
@@ -4,19 +4,26 @@ import (
 	"context"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // User represents a user account in the authentication system.
 type User struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty"`
-	FullName         string             `bson:"full_name"`
-	Email            string             `bson:"email"`
-	PasswordHash     string             `bson:"password_hash"`
-	Verified         bool               `bson:"verified"`
-	VerificationCode string             `bson:"verification_code"`
-	CreatedAt        time.Time          `bson:"created_at"`
-	UpdatedAt        time.Time          `bson:"updated_at"`
+	ID                    bson.ObjectID `bson:"_id,omitempty"`
+	FullName              string        `bson:"full_name"`
+	Email                 string        `bson:"email"`
+	PasswordHash          string        `bson:"password_hash"`
+	Verified              bool          `bson:"verified"`
+	VerificationCode      string        `bson:"verification_code"`
+	PasswordResetRequired bool          `bson:"password_reset_required"`
+	DisplayName           string        `bson:"display_name"`
+	AvatarURL             string        `bson:"avatar_url"`
+	Locale                string        `bson:"locale"`
+	Timezone              string        `bson:"timezone"`
+	DefaultCurrency       string        `bson:"default_currency"`
+	CreatedAt             time.Time     `bson:"created_at"`
+	UpdatedAt             time.Time     `bson:"updated_at"`
 }
 
 // UserRepository defines the interface for user data persistence operations.
@@ -26,23 +33,48 @@ type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	UpdateUser(ctx context.Context, id string, params UpdateUserParams) (*User, error)
 	DeleteUser(ctx context.Context, id string) (*User, error)
-	ListUsers(ctx context.Context, params FilterUserParams) ([]*User, error)
+	ListUsers(ctx context.Context, params FilterUserParams) ([]*User, *contract.Cursor, error)
 }
 
 // UpdateUserParams contains the optional parameters for updating a user.
 // Only non-nil fields will be updated.
 type UpdateUserParams struct {
-	Email        *string
-	FullName     *string
-	PasswordHash *string
+	Email                 *string
+	FullName              *string
+	PasswordHash          *string
+	PasswordResetRequired *bool
+	DisplayName           *string
+	AvatarURL             *string
+	Locale                *string
+	Timezone              *string
+	DefaultCurrency       *string
 }
 
 // FilterUserParams contains the parameters for filtering and paginating user queries.
+// After is the cursor returned with the previous page.
 type FilterUserParams struct {
 	Email    *string
 	Verified *bool
 	Limit    uint64
-	Offset   uint64
+	After    *contract.Cursor
 	SortBy   *string
 	SortDesc bool
 }
+
+// UserUsecase defines the interface for user profile business logics.
+type UserUsecase interface {
+	GetMe(ctx context.Context, userID string) (*User, error)
+	UpdateProfile(ctx context.Context, params UpdateProfileParams) (*User, error)
+}
+
+// UpdateProfileParams contains the optional profile settings to update for a user.
+// Only non-nil fields will be updated.
+type UpdateProfileParams struct {
+	UserID          string
+	FullName        *string
+	DisplayName     *string
+	AvatarURL       *string
+	Locale          *string
+	Timezone        *string
+	DefaultCurrency *string
+}
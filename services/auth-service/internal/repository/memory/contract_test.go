@@ -0,0 +1,27 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/memory"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/repotest"
+)
+
+func TestUserRepository(t *testing.T) {
+	repotest.RunUserRepositoryContract(t, func(_ *testing.T) domain.UserRepository {
+		return memory.NewUserRepository()
+	})
+}
+
+func TestSessionRepository(t *testing.T) {
+	repotest.RunSessionRepositoryContract(t, func(_ *testing.T) domain.SessionRepository {
+		return memory.NewSessionRepository()
+	})
+}
+
+func TestIdentityRepository(t *testing.T) {
+	repotest.RunIdentityRepositoryContract(t, func(_ *testing.T) domain.IdentityRepository {
+		return memory.NewIdentityRepository()
+	})
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type exportJobMemoryRepository struct {
+	mu   sync.RWMutex
+	jobs map[bson.ObjectID]domain.ExportJob
+}
+
+func NewExportJobRepository() domain.ExportJobRepository {
+	return &exportJobMemoryRepository{
+		jobs: make(map[bson.ObjectID]domain.ExportJob),
+	}
+}
+
+func (r *exportJobMemoryRepository) CreateExportJob(
+	_ context.Context,
+	job *domain.ExportJob,
+) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := timestamp()
+	job.ID = bson.NewObjectID()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	r.jobs[job.ID] = *job
+
+	return job, nil
+}
+
+func (r *exportJobMemoryRepository) GetExportJob(_ context.Context, id string) (*domain.ExportJob, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return &job, nil
+}
+
+func (r *exportJobMemoryRepository) UpdateExportJob(
+	_ context.Context,
+	id string,
+	params domain.UpdateExportJobParams,
+) (*domain.ExportJob, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Status == nil && params.Error == nil && params.Archive == nil && params.CompletedAt == nil {
+		return nil, errors.New("no export job fields to update")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	if params.Status != nil {
+		job.Status = *params.Status
+	}
+	if params.Error != nil {
+		job.Error = *params.Error
+	}
+	if params.Archive != nil {
+		job.Archive = params.Archive
+	}
+	if params.CompletedAt != nil {
+		job.CompletedAt = params.CompletedAt
+	}
+	job.UpdatedAt = timestamp()
+	r.jobs[objectID] = job
+
+	return &job, nil
+}
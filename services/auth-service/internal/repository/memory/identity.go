@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type identityMemoryRepository struct {
+	mu         sync.RWMutex
+	identities map[bson.ObjectID]domain.Identity
+}
+
+func NewIdentityRepository() domain.IdentityRepository {
+	return &identityMemoryRepository{
+		identities: make(map[bson.ObjectID]domain.Identity),
+	}
+}
+
+func (r *identityMemoryRepository) CreateIdentity(
+	_ context.Context,
+	identity *domain.Identity,
+) (*domain.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := timestamp()
+	identity.ID = bson.NewObjectID()
+	identity.CreatedAt = now
+	identity.UpdatedAt = now
+	r.identities[identity.ID] = *identity
+
+	return identity, nil
+}
+
+func (r *identityMemoryRepository) GetIdentitiesByUserID(_ context.Context, userID string) ([]domain.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var identities []domain.Identity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+
+	return identities, nil
+}
+
+func (r *identityMemoryRepository) GetIdentityByProvider(
+	_ context.Context,
+	providerID string,
+	provider string,
+) (*domain.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, identity := range r.identities {
+		if identity.ProviderID == providerID && identity.Provider == provider {
+			return &identity, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *identityMemoryRepository) UpdateLastLogin(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := timestamp()
+	for id, identity := range r.identities {
+		if identity.UserID == userID {
+			identity.LastLoginAt = now
+			identity.UpdatedAt = now
+			r.identities[id] = identity
+		}
+	}
+
+	return nil
+}
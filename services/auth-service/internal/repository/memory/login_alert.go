@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type loginAlertMemoryRepository struct {
+	mu     sync.RWMutex
+	alerts map[bson.ObjectID]domain.LoginAlert
+}
+
+func NewLoginAlertRepository() domain.LoginAlertRepository {
+	return &loginAlertMemoryRepository{
+		alerts: make(map[bson.ObjectID]domain.LoginAlert),
+	}
+}
+
+func (r *loginAlertMemoryRepository) CreateLoginAlert(
+	_ context.Context,
+	alert *domain.LoginAlert,
+) (*domain.LoginAlert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.alerts {
+		if existing.TokenHash == alert.TokenHash {
+			return nil, errDuplicateKey("duplicate key error: token_hash")
+		}
+	}
+
+	alert.ID = bson.NewObjectID()
+	alert.CreatedAt = timestamp()
+	r.alerts[alert.ID] = *alert
+
+	return alert, nil
+}
+
+func (r *loginAlertMemoryRepository) GetLoginAlertByTokenHash(
+	_ context.Context,
+	tokenHash string,
+) (*domain.LoginAlert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, alert := range r.alerts {
+		if alert.TokenHash == tokenHash {
+			return &alert, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *loginAlertMemoryRepository) MarkLoginAlertUsed(_ context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, ok := r.alerts[objectID]
+	if !ok {
+		return nil
+	}
+
+	now := timestamp()
+	alert.UsedAt = &now
+	r.alerts[objectID] = alert
+
+	return nil
+}
+
+func (r *loginAlertMemoryRepository) ListLoginAlertsByUserID(
+	_ context.Context,
+	userID string,
+) ([]domain.LoginAlert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []domain.LoginAlert
+	for _, alert := range r.alerts {
+		if alert.UserID == userID {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts, nil
+}
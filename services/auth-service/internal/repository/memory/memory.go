@@ -0,0 +1,25 @@
+// Package memory provides in-memory implementations of the auth-service repositories.
+// They mirror the behavior of the MongoDB repositories, including the driver errors the
+// use cases rely on, and are intended for tests and local development.
+package memory
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// duplicateKeyErrorCode is the MongoDB server error code for unique index violations.
+const duplicateKeyErrorCode = 11000
+
+// errDuplicateKey returns an error that satisfies mongo.IsDuplicateKeyError.
+func errDuplicateKey(message string) error {
+	return mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: duplicateKeyErrorCode, Message: message}},
+	}
+}
+
+// timestamp returns the current time truncated to the millisecond precision MongoDB stores.
+func timestamp() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type passwordResetMemoryRepository struct {
+	mu     sync.RWMutex
+	resets map[bson.ObjectID]domain.PasswordReset
+}
+
+func NewPasswordResetRepository() domain.PasswordResetRepository {
+	return &passwordResetMemoryRepository{
+		resets: make(map[bson.ObjectID]domain.PasswordReset),
+	}
+}
+
+func (r *passwordResetMemoryRepository) CreatePasswordReset(
+	_ context.Context,
+	reset *domain.PasswordReset,
+) (*domain.PasswordReset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.resets {
+		if existing.TokenHash == reset.TokenHash {
+			return nil, errDuplicateKey("duplicate key error: token_hash")
+		}
+	}
+
+	reset.ID = bson.NewObjectID()
+	reset.CreatedAt = timestamp()
+	r.resets[reset.ID] = *reset
+
+	return reset, nil
+}
+
+func (r *passwordResetMemoryRepository) GetPasswordResetByTokenHash(
+	_ context.Context,
+	tokenHash string,
+) (*domain.PasswordReset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, reset := range r.resets {
+		if reset.TokenHash == tokenHash {
+			return &reset, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *passwordResetMemoryRepository) MarkPasswordResetUsed(_ context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reset, ok := r.resets[objectID]
+	if !ok || reset.UsedAt != nil {
+		return mongo.ErrNoDocuments
+	}
+
+	now := timestamp()
+	reset.UsedAt = &now
+	r.resets[objectID] = reset
+
+	return nil
+}
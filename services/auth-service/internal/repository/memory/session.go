@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type sessionMemoryRepository struct {
+	mu       sync.RWMutex
+	sessions map[bson.ObjectID]domain.Session
+}
+
+func NewSessionRepository() domain.SessionRepository {
+	return &sessionMemoryRepository{
+		sessions: make(map[bson.ObjectID]domain.Session),
+	}
+}
+
+func (r *sessionMemoryRepository) CreateSession(_ context.Context, session *domain.Session) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := timestamp()
+	session.ID = bson.NewObjectID()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	r.sessions[session.ID] = *session
+
+	return session, nil
+}
+
+func (r *sessionMemoryRepository) GetSession(_ context.Context, id string) (*domain.Session, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return &session, nil
+}
+
+func (r *sessionMemoryRepository) GetSessionByUserID(ctx context.Context, userID string) (*domain.Session, error) {
+	sessions, err := r.ListSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return &sessions[0], nil
+}
+
+func (r *sessionMemoryRepository) ListSessionsByUserID(_ context.Context, userID string) ([]domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []domain.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		cmp := sessions[i].CreatedAt.Compare(sessions[j].CreatedAt)
+		if cmp == 0 {
+			cmp = strings.Compare(sessions[i].ID.Hex(), sessions[j].ID.Hex())
+		}
+		return cmp > 0
+	})
+
+	return sessions, nil
+}
+
+func (r *sessionMemoryRepository) UpdateTokens(
+	_ context.Context,
+	id string,
+	params domain.UpdateTokensParams,
+) (*domain.Session, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[objectID]
+	if !ok || (params.PreviousRefreshToken != "" && session.RefreshToken != params.PreviousRefreshToken) {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	session.AccessToken = params.AccessToken
+	session.RefreshToken = params.RefreshToken
+	session.AccessTokenExpiresAt = params.AccessTokenExpiresAt.Truncate(time.Millisecond)
+	session.RefreshTokenExpiresAt = params.RefreshTokenExpiresAt.Truncate(time.Millisecond)
+	session.UpdatedAt = timestamp()
+	r.sessions[objectID] = session
+
+	return &session, nil
+}
+
+func (r *sessionMemoryRepository) RevokeSession(_ context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[objectID]
+	if !ok {
+		return nil
+	}
+
+	now := timestamp()
+	session.RevokedAt = &now
+	session.UpdatedAt = now
+	r.sessions[objectID] = session
+
+	return nil
+}
+
+func (r *sessionMemoryRepository) CountActiveSessions(_ context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+
+	var count int64
+	for _, session := range r.sessions {
+		if session.RevokedAt == nil && session.RefreshTokenExpiresAt.After(now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
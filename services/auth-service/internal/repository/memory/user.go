@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type userMemoryRepository struct {
+	mu    sync.RWMutex
+	users map[bson.ObjectID]domain.User
+}
+
+func NewUserRepository() domain.UserRepository {
+	return &userMemoryRepository{
+		users: make(map[bson.ObjectID]domain.User),
+	}
+}
+
+func (r *userMemoryRepository) CreateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user.Email, bson.NilObjectID) {
+		return nil, errDuplicateKey("duplicate key error: email")
+	}
+
+	now := timestamp()
+	user.ID = bson.NewObjectID()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.users[user.ID] = *user
+
+	return user, nil
+}
+
+func (r *userMemoryRepository) GetUser(_ context.Context, id string) (*domain.User, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return &user, nil
+}
+
+func (r *userMemoryRepository) GetUserByEmail(_ context.Context, email string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *userMemoryRepository) UpdateUser(
+	_ context.Context,
+	id string,
+	params domain.UpdateUserParams,
+) (*domain.User, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params == (domain.UpdateUserParams{}) {
+		return nil, errors.New("no user fields to update")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	if params.Email != nil {
+		if r.emailTaken(*params.Email, objectID) {
+			return nil, errDuplicateKey("duplicate key error: email")
+		}
+		user.Email = *params.Email
+	}
+	if params.FullName != nil {
+		user.FullName = *params.FullName
+	}
+	if params.PasswordHash != nil {
+		user.PasswordHash = *params.PasswordHash
+	}
+	if params.PasswordResetRequired != nil {
+		user.PasswordResetRequired = *params.PasswordResetRequired
+	}
+	if params.DisplayName != nil {
+		user.DisplayName = *params.DisplayName
+	}
+	if params.AvatarURL != nil {
+		user.AvatarURL = *params.AvatarURL
+	}
+	if params.Locale != nil {
+		user.Locale = *params.Locale
+	}
+	if params.Timezone != nil {
+		user.Timezone = *params.Timezone
+	}
+	if params.DefaultCurrency != nil {
+		user.DefaultCurrency = *params.DefaultCurrency
+	}
+
+	user.UpdatedAt = timestamp()
+	r.users[objectID] = user
+
+	return &user, nil
+}
+
+func (r *userMemoryRepository) DeleteUser(_ context.Context, id string) (*domain.User, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[objectID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	delete(r.users, objectID)
+
+	return &user, nil
+}
+
+func (r *userMemoryRepository) ListUsers(
+	_ context.Context,
+	params domain.FilterUserParams,
+) ([]*domain.User, *contract.Cursor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sortBy := userSortField(params.SortBy)
+	compare := func(a, b *domain.User) int {
+		cmp := compareUsers(a, b, sortBy)
+		if cmp == 0 {
+			cmp = strings.Compare(a.ID.Hex(), b.ID.Hex())
+		}
+		if params.SortDesc {
+			return -cmp
+		}
+		return cmp
+	}
+
+	var after *domain.User
+	if params.After != nil {
+		var err error
+		if after, err = cursorUser(params.After, sortBy); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var users []*domain.User
+	for _, user := range r.users {
+		if params.Email != nil && user.Email != *params.Email {
+			continue
+		}
+		if params.Verified != nil && user.Verified != *params.Verified {
+			continue
+		}
+		if after != nil && compare(&user, after) <= 0 {
+			continue
+		}
+		users = append(users, &user)
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return compare(users[i], users[j]) < 0
+	})
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	if uint64(len(users)) <= limit {
+		return users, nil, nil
+	}
+
+	users = users[:limit]
+	last := users[len(users)-1]
+
+	return users, &contract.Cursor{Sort: sortBy, Key: userSortKey(last, sortBy), ID: last.ID.Hex()}, nil
+}
+
+// emailTaken reports whether another user already uses the email. Callers must hold the lock.
+func (r *userMemoryRepository) emailTaken(email string, exceptID bson.ObjectID) bool {
+	for id, user := range r.users {
+		if id != exceptID && user.Email == email {
+			return true
+		}
+	}
+
+	return false
+}
+
+// userSortField returns the field to sort users by. Users can be sorted by email, full_name,
+// created_at and updated_at, and are sorted by created_at by default.
+func userSortField(sortBy *string) string {
+	if sortBy != nil {
+		switch *sortBy {
+		case "email", "full_name", "updated_at":
+			return *sortBy
+		}
+	}
+
+	return "created_at"
+}
+
+// userSortKey returns the value of the field users are sorted by.
+func userSortKey(user *domain.User, field string) any {
+	switch field {
+	case "email":
+		return user.Email
+	case "full_name":
+		return user.FullName
+	case "updated_at":
+		return user.UpdatedAt
+	default:
+		return user.CreatedAt
+	}
+}
+
+// cursorUser returns a user holding the position of the cursor, to compare users with.
+func cursorUser(cursor *contract.Cursor, sortBy string) (*domain.User, error) {
+	id, err := bson.ObjectIDFromHex(cursor.ID)
+	if err != nil || cursor.Sort != sortBy {
+		return nil, contract.ErrInvalidCursor
+	}
+
+	user := &domain.User{ID: id}
+	switch key := cursor.Key.(type) {
+	case string:
+		user.Email, user.FullName = key, key
+	case time.Time:
+		user.CreatedAt, user.UpdatedAt = key, key
+	case bson.DateTime:
+		user.CreatedAt, user.UpdatedAt = key.Time(), key.Time()
+	default:
+		return nil, contract.ErrInvalidCursor
+	}
+
+	return user, nil
+}
+
+// compareUsers returns -1, 0 or 1 depending on how a and b compare on the given field.
+func compareUsers(a, b *domain.User, field string) int {
+	switch field {
+	case "email":
+		return strings.Compare(a.Email, b.Email)
+	case "full_name":
+		return strings.Compare(a.FullName, b.FullName)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
@@ -0,0 +1,171 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const authEventCollection = "auth_events"
+
+type authEventMongoRepository struct {
+	db *mongo.Database
+}
+
+func NewAuthEventRepository(ctx context.Context, logger *zerolog.Logger, db *mongo.Database) domain.AuthEventRepository {
+	collection := db.Collection(authEventCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create auth event indexes")
+	}
+
+	return &authEventMongoRepository{
+		db: db,
+	}
+}
+
+func (r *authEventMongoRepository) CreateAuthEvent(
+	ctx context.Context,
+	event *domain.AuthEvent,
+) (*domain.AuthEvent, error) {
+	event.CreatedAt = time.Now()
+
+	result, err := r.db.Collection(authEventCollection).InsertOne(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	objectID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to convert inserted ID to ObjectID")
+	}
+	event.ID = objectID
+
+	return event, nil
+}
+
+func (r *authEventMongoRepository) ListAuthEventsByUserID(
+	ctx context.Context,
+	userID string,
+	params domain.FilterAuthEventParams,
+) ([]domain.AuthEvent, *contract.Cursor, error) {
+	limit := params.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	page := database.PageQuery{
+		SortField: "created_at",
+		SortDesc:  true,
+		Limit:     int64(limit),
+		After:     params.After,
+	}
+
+	filter, err := page.Filter(bson.M{"user_id": userID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cursor, err := r.db.Collection(authEventCollection).Find(ctx, filter, page.FindOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []domain.AuthEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, nil, err
+	}
+
+	events, next := database.Page(events, page, func(event domain.AuthEvent) (any, bson.ObjectID) {
+		return event.CreatedAt, event.ID
+	})
+
+	return events, next, nil
+}
+
+func (r *authEventMongoRepository) ListAuthEventsAfter(
+	ctx context.Context,
+	userID string,
+	afterID string,
+	limit uint64,
+) ([]domain.AuthEvent, error) {
+	objectID, err := bson.ObjectIDFromHex(afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.db.Collection(authEventCollection).Find(
+		ctx,
+		bson.M{"user_id": userID, "_id": bson.M{"$gt": objectID}},
+		findOptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []domain.AuthEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *authEventMongoRepository) ListAuthEventsSince(
+	ctx context.Context,
+	since time.Time,
+	limit uint64,
+	after *contract.Cursor,
+) ([]domain.AuthEvent, *contract.Cursor, error) {
+	page := database.PageQuery{
+		SortField: "created_at",
+		Limit:     int64(limit),
+		After:     after,
+	}
+
+	filter, err := page.Filter(bson.M{"created_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cursor, err := r.db.Collection(authEventCollection).Find(ctx, filter, page.FindOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []domain.AuthEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, nil, err
+	}
+
+	events, next := database.Page(events, page, func(event domain.AuthEvent) (any, bson.ObjectID) {
+		return event.CreatedAt, event.ID
+	})
+
+	return events, next, nil
+}
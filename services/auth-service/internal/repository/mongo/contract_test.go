@@ -0,0 +1,58 @@
+package mongo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	mongodb "github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/mongo"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/repotest"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// newTestDatabase returns a throwaway database on the MongoDB instance at MONGO_TEST_URI.
+// Tests are skipped when the variable is not set.
+func newTestDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	db := client.Database("auth_contract_" + bson.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	return db
+}
+
+func TestUserRepository(t *testing.T) {
+	repotest.RunUserRepositoryContract(t, func(t *testing.T) domain.UserRepository {
+		return mongodb.NewUserRepository(context.Background(), logger.Get(), newTestDatabase(t))
+	})
+}
+
+func TestSessionRepository(t *testing.T) {
+	repotest.RunSessionRepositoryContract(t, func(t *testing.T) domain.SessionRepository {
+		return mongodb.NewSessionRepository(context.Background(), logger.Get(), newTestDatabase(t))
+	})
+}
+
+func TestIdentityRepository(t *testing.T) {
+	repotest.RunIdentityRepositoryContract(t, func(t *testing.T) domain.IdentityRepository {
+		return mongodb.NewIdentityRepository(context.Background(), logger.Get(), newTestDatabase(t))
+	})
+}
@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	exportJobCollection = "export_jobs"
+
+	// exportJobTTL is how long an export job and its archive are kept before MongoDB removes them.
+	exportJobTTL = 7 * 24 * time.Hour
+)
+
+type exportJobMongoRepository struct {
+	db *mongo.Database
+}
+
+func NewExportJobRepository(ctx context.Context, logger *zerolog.Logger, db *mongo.Database) domain.ExportJobRepository {
+	collection := db.Collection(exportJobCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(exportJobTTL.Seconds())),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create export job indexes")
+	}
+
+	return &exportJobMongoRepository{
+		db: db,
+	}
+}
+
+func (r *exportJobMongoRepository) CreateExportJob(
+	ctx context.Context,
+	job *domain.ExportJob,
+) (*domain.ExportJob, error) {
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	result, err := r.db.Collection(exportJobCollection).InsertOne(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	objectID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to convert inserted ID to ObjectID")
+	}
+	job.ID = objectID
+
+	return job, nil
+}
+
+func (r *exportJobMongoRepository) GetExportJob(ctx context.Context, id string) (*domain.ExportJob, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := r.db.Collection(exportJobCollection).FindOne(ctx, bson.M{"_id": objectID})
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var job domain.ExportJob
+	if err := result.Decode(&job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *exportJobMongoRepository) UpdateExportJob(
+	ctx context.Context,
+	id string,
+	params domain.UpdateExportJobParams,
+) (*domain.ExportJob, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build update query
+	updateMap := bson.M{}
+	if params.Status != nil {
+		updateMap["status"] = *params.Status
+	}
+	if params.Error != nil {
+		updateMap["error"] = *params.Error
+	}
+	if params.Archive != nil {
+		updateMap["archive"] = params.Archive
+	}
+	if params.CompletedAt != nil {
+		updateMap["completed_at"] = *params.CompletedAt
+	}
+
+	if len(updateMap) == 0 {
+		return nil, errors.New("no export job fields to update")
+	}
+
+	updateMap["updated_at"] = time.Now()
+
+	result := r.db.Collection(exportJobCollection).FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": updateMap},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var job domain.ExportJob
+	if err := result.Decode(&job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
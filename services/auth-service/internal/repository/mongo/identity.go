@@ -7,7 +7,6 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -37,7 +36,7 @@ func (r *identityMongoRepository) CreateIdentity(
 		return nil, err
 	}
 
-	objectID, ok := result.InsertedID.(primitive.ObjectID)
+	objectID, ok := result.InsertedID.(bson.ObjectID)
 	if !ok {
 		return nil, errors.New("failed to convert inserted ID to ObjectID")
 	}
@@ -47,12 +46,7 @@ func (r *identityMongoRepository) CreateIdentity(
 }
 
 func (r *identityMongoRepository) GetIdentitiesByUserID(ctx context.Context, userID string) ([]domain.Identity, error) {
-	objectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, err
-	}
-
-	cursor, err := r.db.Collection(identityCollection).Find(ctx, bson.M{"user_id": objectID})
+	cursor, err := r.db.Collection(identityCollection).Find(ctx, bson.M{"user_id": userID})
 	if err != nil {
 		return nil, err
 	}
@@ -87,15 +81,11 @@ func (r *identityMongoRepository) GetIdentityByProvider(
 }
 
 func (r *identityMongoRepository) UpdateLastLogin(ctx context.Context, userID string) error {
-	objectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return err
-	}
-
-	_, err = r.db.Collection(identityCollection).UpdateOne(
+	now := time.Now()
+	_, err := r.db.Collection(identityCollection).UpdateMany(
 		ctx,
-		bson.M{"user_id": objectID},
-		bson.M{"$set": bson.M{"last_login_at": time.Now()}},
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"last_login_at": now, "updated_at": now}},
 	)
 	return err
 }
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const knownDeviceCollection = "known_devices"
+
+type knownDeviceMongoRepository struct {
+	db *mongo.Database
+}
+
+func NewKnownDeviceRepository(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	db *mongo.Database,
+) domain.KnownDeviceRepository {
+	collection := db.Collection(knownDeviceCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "fingerprint", Value: 1},
+				{Key: "ip_address", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create known device indexes")
+	}
+
+	return &knownDeviceMongoRepository{
+		db: db,
+	}
+}
+
+func (r *knownDeviceMongoRepository) GetKnownDevicesByUserID(
+	ctx context.Context,
+	userID string,
+) ([]domain.KnownDevice, error) {
+	cursor, err := r.db.Collection(knownDeviceCollection).Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []domain.KnownDevice
+	if err := cursor.All(ctx, &devices); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (r *knownDeviceMongoRepository) UpsertKnownDevice(ctx context.Context, device *domain.KnownDevice) error {
+	now := time.Now()
+
+	_, err := r.db.Collection(knownDeviceCollection).UpdateOne(
+		ctx,
+		bson.M{
+			"user_id":     device.UserID,
+			"fingerprint": device.Fingerprint,
+			"ip_address":  device.IPAddress,
+		},
+		bson.M{
+			"$set":         bson.M{"user_agent": device.UserAgent, "last_seen_at": now},
+			"$setOnInsert": bson.M{"first_seen_at": now},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const loginAlertCollection = "login_alerts"
+
+type loginAlertMongoRepository struct {
+	db *mongo.Database
+}
+
+func NewLoginAlertRepository(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	db *mongo.Database,
+) domain.LoginAlertRepository {
+	collection := db.Collection(loginAlertCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create login alert indexes")
+	}
+
+	return &loginAlertMongoRepository{
+		db: db,
+	}
+}
+
+func (r *loginAlertMongoRepository) CreateLoginAlert(
+	ctx context.Context,
+	alert *domain.LoginAlert,
+) (*domain.LoginAlert, error) {
+	alert.CreatedAt = time.Now()
+
+	result, err := r.db.Collection(loginAlertCollection).InsertOne(ctx, alert)
+	if err != nil {
+		return nil, err
+	}
+
+	objectID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to convert inserted ID to ObjectID")
+	}
+	alert.ID = objectID
+
+	return alert, nil
+}
+
+func (r *loginAlertMongoRepository) GetLoginAlertByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*domain.LoginAlert, error) {
+	result := r.db.Collection(loginAlertCollection).FindOne(ctx, bson.M{"token_hash": tokenHash})
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var alert domain.LoginAlert
+	if err := result.Decode(&alert); err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+func (r *loginAlertMongoRepository) MarkLoginAlertUsed(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection(loginAlertCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *loginAlertMongoRepository) ListLoginAlertsByUserID(
+	ctx context.Context,
+	userID string,
+) ([]domain.LoginAlert, error) {
+	cursor, err := r.db.Collection(loginAlertCollection).Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []domain.LoginAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const passwordResetCollection = "password_resets"
+
+type passwordResetMongoRepository struct {
+	db *mongo.Database
+}
+
+func NewPasswordResetRepository(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	db *mongo.Database,
+) domain.PasswordResetRepository {
+	collection := db.Collection(passwordResetCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create password reset indexes")
+	}
+
+	return &passwordResetMongoRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetMongoRepository) CreatePasswordReset(
+	ctx context.Context,
+	reset *domain.PasswordReset,
+) (*domain.PasswordReset, error) {
+	reset.CreatedAt = time.Now()
+
+	result, err := r.db.Collection(passwordResetCollection).InsertOne(ctx, reset)
+	if err != nil {
+		return nil, err
+	}
+
+	objectID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to convert inserted ID to ObjectID")
+	}
+	reset.ID = objectID
+
+	return reset, nil
+}
+
+func (r *passwordResetMongoRepository) GetPasswordResetByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*domain.PasswordReset, error) {
+	result := r.db.Collection(passwordResetCollection).FindOne(ctx, bson.M{"token_hash": tokenHash})
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var reset domain.PasswordReset
+	if err := result.Decode(&reset); err != nil {
+		return nil, err
+	}
+
+	return &reset, nil
+}
+
+func (r *passwordResetMongoRepository) MarkPasswordResetUsed(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Collection(passwordResetCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
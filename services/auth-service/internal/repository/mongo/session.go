@@ -7,9 +7,9 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 const sessionCollection = "sessions"
@@ -34,7 +34,7 @@ func (r *sessionMongoRepository) CreateSession(ctx context.Context, session *dom
 		return nil, err
 	}
 
-	objectID, ok := result.InsertedID.(primitive.ObjectID)
+	objectID, ok := result.InsertedID.(bson.ObjectID)
 	if !ok {
 		return nil, errors.New("failed to convert inserted ID to ObjectID")
 	}
@@ -43,8 +43,8 @@ func (r *sessionMongoRepository) CreateSession(ctx context.Context, session *dom
 	return session, nil
 }
 
-func (r *sessionMongoRepository) GetSessionByUserID(ctx context.Context, userID string) (*domain.Session, error) {
-	objectID, err := primitive.ObjectIDFromHex(userID)
+func (r *sessionMongoRepository) GetSession(ctx context.Context, id string) (*domain.Session, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
@@ -62,15 +62,51 @@ func (r *sessionMongoRepository) GetSessionByUserID(ctx context.Context, userID
 	return &session, nil
 }
 
+// GetSessionByUserID returns the user's most recently created session.
+func (r *sessionMongoRepository) GetSessionByUserID(ctx context.Context, userID string) (*domain.Session, error) {
+	result := r.db.Collection(sessionCollection).FindOne(
+		ctx,
+		bson.M{"user_id": userID},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var session domain.Session
+	if err := result.Decode(&session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
 func (r *sessionMongoRepository) UpdateTokens(
 	ctx context.Context,
 	id string,
 	params domain.UpdateTokensParams,
 ) (*domain.Session, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectID}
+	if params.PreviousRefreshToken != "" {
+		filter["refresh_token"] = params.PreviousRefreshToken
+	}
+
 	result := r.db.Collection(sessionCollection).FindOneAndUpdate(
 		ctx,
-		bson.M{"_id": id},
-		bson.M{"$set": params},
+		filter,
+		bson.M{"$set": bson.M{
+			"access_token":             params.AccessToken,
+			"refresh_token":            params.RefreshToken,
+			"access_token_expires_at":  params.AccessTokenExpiresAt,
+			"refresh_token_expires_at": params.RefreshTokenExpiresAt,
+			"updated_at":               time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	)
 	if result.Err() != nil {
 		return nil, result.Err()
@@ -83,3 +119,44 @@ func (r *sessionMongoRepository) UpdateTokens(
 
 	return &session, nil
 }
+
+func (r *sessionMongoRepository) ListSessionsByUserID(ctx context.Context, userID string) ([]domain.Session, error) {
+	cursor, err := r.db.Collection(sessionCollection).Find(
+		ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []domain.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionMongoRepository) RevokeSession(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = r.db.Collection(sessionCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"revoked_at": now, "updated_at": now}},
+	)
+	return err
+}
+
+// CountActiveSessions counts the sessions that are neither revoked nor past their refresh token expiry.
+func (r *sessionMongoRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	return r.db.Collection(sessionCollection).CountDocuments(ctx, bson.M{
+		"revoked_at":               bson.M{"$exists": false},
+		"refresh_token_expires_at": bson.M{"$gt": time.Now()},
+	})
+}
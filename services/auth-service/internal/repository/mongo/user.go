@@ -7,7 +7,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/database"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -49,7 +50,7 @@ func (r *userMongoRepository) CreateUser(ctx context.Context, user *domain.User)
 		return nil, err
 	}
 
-	objectID, ok := result.InsertedID.(primitive.ObjectID)
+	objectID, ok := result.InsertedID.(bson.ObjectID)
 	if !ok {
 		return nil, errors.New("failed to convert inserted ID to ObjectID")
 	}
@@ -59,7 +60,7 @@ func (r *userMongoRepository) CreateUser(ctx context.Context, user *domain.User)
 }
 
 func (r *userMongoRepository) GetUser(ctx context.Context, id string) (*domain.User, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +97,7 @@ func (r *userMongoRepository) UpdateUser(
 	id string,
 	params domain.UpdateUserParams,
 ) (*domain.User, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +113,24 @@ func (r *userMongoRepository) UpdateUser(
 	if params.PasswordHash != nil {
 		updateMap["password_hash"] = params.PasswordHash
 	}
+	if params.PasswordResetRequired != nil {
+		updateMap["password_reset_required"] = params.PasswordResetRequired
+	}
+	if params.DisplayName != nil {
+		updateMap["display_name"] = params.DisplayName
+	}
+	if params.AvatarURL != nil {
+		updateMap["avatar_url"] = params.AvatarURL
+	}
+	if params.Locale != nil {
+		updateMap["locale"] = params.Locale
+	}
+	if params.Timezone != nil {
+		updateMap["timezone"] = params.Timezone
+	}
+	if params.DefaultCurrency != nil {
+		updateMap["default_currency"] = params.DefaultCurrency
+	}
 
 	if len(updateMap) == 0 {
 		return nil, errors.New("no user fields to update")
@@ -138,7 +157,7 @@ func (r *userMongoRepository) UpdateUser(
 }
 
 func (r *userMongoRepository) DeleteUser(ctx context.Context, id string) (*domain.User, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
@@ -156,30 +175,23 @@ func (r *userMongoRepository) DeleteUser(ctx context.Context, id string) (*domai
 	return &user, nil
 }
 
-func (r *userMongoRepository) ListUsers(ctx context.Context, params domain.FilterUserParams) ([]*domain.User, error) {
-	findOptions := options.Find()
-
+func (r *userMongoRepository) ListUsers(
+	ctx context.Context,
+	params domain.FilterUserParams,
+) ([]*domain.User, *contract.Cursor, error) {
 	limit := params.Limit
 	if limit == 0 {
 		limit = 10
 	}
-	findOptions.SetLimit(int64(limit))
-
-	if params.Offset > 0 {
-		findOptions.SetSkip(int64(params.Offset))
-	}
 
-	sortBy := "created_at"
-	if params.SortBy != nil {
-		sortBy = *params.SortBy
+	sortBy := userSortField(params.SortBy)
+	page := database.PageQuery{
+		SortField: sortBy,
+		SortDesc:  params.SortDesc,
+		Limit:     int64(limit),
+		After:     params.After,
 	}
 
-	sortOrder := -1
-	if !params.SortDesc {
-		sortOrder = 1
-	}
-	findOptions.SetSort(bson.D{{Key: sortBy, Value: sortOrder}})
-
 	// Build filter query
 	filter := bson.M{}
 	if params.Email != nil {
@@ -189,9 +201,14 @@ func (r *userMongoRepository) ListUsers(ctx context.Context, params domain.Filte
 		filter["verified"] = *params.Verified
 	}
 
-	cursor, err := r.db.Collection(userCollection).Find(ctx, filter, findOptions)
+	filter, err := page.Filter(filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	cursor, err := r.db.Collection(userCollection).Find(ctx, filter, page.FindOptions())
+	if err != nil {
+		return nil, nil, err
 	}
 	defer cursor.Close(ctx)
 
@@ -199,14 +216,45 @@ func (r *userMongoRepository) ListUsers(ctx context.Context, params domain.Filte
 	for cursor.Next(ctx) {
 		var user domain.User
 		if err := cursor.Decode(&user); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		users = append(users, &user)
 	}
 
 	if err := cursor.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return users, nil
+	users, next := database.Page(users, page, func(user *domain.User) (any, bson.ObjectID) {
+		return userSortKey(user, sortBy), user.ID
+	})
+
+	return users, next, nil
+}
+
+// userSortField returns the field to sort users by. Users can be sorted by email, full_name,
+// created_at and updated_at, and are sorted by created_at by default.
+func userSortField(sortBy *string) string {
+	if sortBy != nil {
+		switch *sortBy {
+		case "email", "full_name", "updated_at":
+			return *sortBy
+		}
+	}
+
+	return "created_at"
+}
+
+// userSortKey returns the value of the field users are sorted by.
+func userSortKey(user *domain.User, field string) any {
+	switch field {
+	case "email":
+		return user.Email
+	case "full_name":
+		return user.FullName
+	case "updated_at":
+		return user.UpdatedAt
+	default:
+		return user.CreatedAt
+	}
 }
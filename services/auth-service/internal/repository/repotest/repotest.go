@@ -0,0 +1,580 @@
+// Package repotest provides contract test suites shared by every implementation of the
+// auth-service repositories, so that the MongoDB repositories and the in-memory fakes are
+// held to the same behavior.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// UserRepositoryFactory creates an empty user repository for a single test.
+type UserRepositoryFactory func(t *testing.T) domain.UserRepository
+
+// SessionRepositoryFactory creates an empty session repository for a single test.
+type SessionRepositoryFactory func(t *testing.T) domain.SessionRepository
+
+// IdentityRepositoryFactory creates an empty identity repository for a single test.
+type IdentityRepositoryFactory func(t *testing.T) domain.IdentityRepository
+
+// RunUserRepositoryContract runs the domain.UserRepository contract against the factory's repositories.
+func RunUserRepositoryContract(t *testing.T, newRepo UserRepositoryFactory) {
+	t.Helper()
+
+	t.Run("CreateUser assigns an ID and timestamps", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := mustCreateUser(t, repo, "alice@example.com", "Alice")
+		if user.ID.IsZero() {
+			t.Fatal("expected ID to be assigned")
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Fatal("expected timestamps to be assigned")
+		}
+
+		got, err := repo.GetUser(ctx, user.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetUser: %v", err)
+		}
+		if got.ID != user.ID || got.Email != user.Email || got.FullName != user.FullName {
+			t.Fatalf("GetUser returned %+v, want %+v", got, user)
+		}
+	})
+
+	t.Run("CreateUser rejects a duplicate email", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustCreateUser(t, repo, "alice@example.com", "Alice")
+		_, err := repo.CreateUser(context.Background(), &domain.User{Email: "alice@example.com"})
+		if !mongo.IsDuplicateKeyError(err) {
+			t.Fatalf("expected duplicate key error, got %v", err)
+		}
+	})
+
+	t.Run("GetUser reports missing and malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		if _, err := repo.GetUser(ctx, bson.NewObjectID().Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+		if _, err := repo.GetUser(ctx, "not-an-object-id"); err == nil {
+			t.Fatal("expected an error for a malformed ID")
+		}
+	})
+
+	t.Run("GetUserByEmail finds the user", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := mustCreateUser(t, repo, "alice@example.com", "Alice")
+
+		got, err := repo.GetUserByEmail(ctx, "alice@example.com")
+		if err != nil {
+			t.Fatalf("GetUserByEmail: %v", err)
+		}
+		if got.ID != user.ID {
+			t.Fatalf("GetUserByEmail returned user %s, want %s", got.ID.Hex(), user.ID.Hex())
+		}
+
+		if _, err := repo.GetUserByEmail(ctx, "bob@example.com"); !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+	})
+
+	t.Run("UpdateUser applies only the given fields", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := mustCreateUser(t, repo, "alice@example.com", "Alice")
+
+		fullName := "Alice Smith"
+		timezone := "Asia/Bangkok"
+		got, err := repo.UpdateUser(ctx, user.ID.Hex(), domain.UpdateUserParams{
+			FullName: &fullName,
+			Timezone: &timezone,
+		})
+		if err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+		if got.FullName != fullName || got.Timezone != timezone {
+			t.Fatalf("UpdateUser returned %+v, want full name %q and timezone %q", got, fullName, timezone)
+		}
+		if got.Email != user.Email {
+			t.Fatalf("UpdateUser changed email to %q", got.Email)
+		}
+
+		stored, err := repo.GetUser(ctx, user.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetUser: %v", err)
+		}
+		if stored.FullName != fullName {
+			t.Fatalf("stored full name is %q, want %q", stored.FullName, fullName)
+		}
+	})
+
+	t.Run("UpdateUser rejects empty updates and missing users", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := mustCreateUser(t, repo, "alice@example.com", "Alice")
+		if _, err := repo.UpdateUser(ctx, user.ID.Hex(), domain.UpdateUserParams{}); err == nil {
+			t.Fatal("expected an error for an empty update")
+		}
+
+		fullName := "Nobody"
+		_, err := repo.UpdateUser(ctx, bson.NewObjectID().Hex(), domain.UpdateUserParams{FullName: &fullName})
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+	})
+
+	t.Run("DeleteUser removes the user", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := mustCreateUser(t, repo, "alice@example.com", "Alice")
+
+		deleted, err := repo.DeleteUser(ctx, user.ID.Hex())
+		if err != nil {
+			t.Fatalf("DeleteUser: %v", err)
+		}
+		if deleted.ID != user.ID {
+			t.Fatalf("DeleteUser returned user %s, want %s", deleted.ID.Hex(), user.ID.Hex())
+		}
+
+		if _, err := repo.GetUser(ctx, user.ID.Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments after delete, got %v", err)
+		}
+	})
+
+	t.Run("ListUsers filters, sorts and paginates", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for _, email := range []string{"carol@example.com", "alice@example.com", "bob@example.com"} {
+			mustCreateUser(t, repo, email, email)
+		}
+		if _, err := repo.CreateUser(ctx, &domain.User{Email: "dave@example.com", Verified: true}); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		sortBy := "email"
+		users, next, err := repo.ListUsers(ctx, domain.FilterUserParams{SortBy: &sortBy, Limit: 2})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "alice@example.com", "bob@example.com")
+		if next == nil {
+			t.Fatal("ListUsers returned no cursor, want one for the next page")
+		}
+
+		after := roundTrip(t, next)
+		users, next, err = repo.ListUsers(ctx, domain.FilterUserParams{SortBy: &sortBy, Limit: 2, After: after})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "carol@example.com", "dave@example.com")
+		if next != nil {
+			t.Fatalf("ListUsers returned cursor %+v on the last page, want none", next)
+		}
+
+		users, _, err = repo.ListUsers(ctx, domain.FilterUserParams{SortBy: &sortBy, SortDesc: true})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "dave@example.com", "carol@example.com", "bob@example.com", "alice@example.com")
+
+		verified := true
+		users, _, err = repo.ListUsers(ctx, domain.FilterUserParams{Verified: &verified})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "dave@example.com")
+	})
+
+	t.Run("ListUsers pages by creation time without repeating users", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for _, email := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+			mustCreateUser(t, repo, email, email)
+		}
+
+		users, next, err := repo.ListUsers(ctx, domain.FilterUserParams{Limit: 2})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "alice@example.com", "bob@example.com")
+
+		mustCreateUser(t, repo, "dave@example.com", "dave@example.com")
+
+		users, _, err = repo.ListUsers(ctx, domain.FilterUserParams{Limit: 2, After: roundTrip(t, next)})
+		if err != nil {
+			t.Fatalf("ListUsers: %v", err)
+		}
+		assertEmails(t, users, "carol@example.com", "dave@example.com")
+
+		sortBy := "email"
+		_, _, err = repo.ListUsers(ctx, domain.FilterUserParams{SortBy: &sortBy, After: next})
+		if !errors.Is(err, contract.ErrInvalidCursor) {
+			t.Fatalf("ListUsers with a cursor of another order returned %v, want ErrInvalidCursor", err)
+		}
+	})
+}
+
+// RunSessionRepositoryContract runs the domain.SessionRepository contract against the factory's repositories.
+func RunSessionRepositoryContract(t *testing.T, newRepo SessionRepositoryFactory) {
+	t.Helper()
+
+	t.Run("GetSession looks sessions up by ID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		session := mustCreateSession(t, repo, bson.NewObjectID().Hex())
+
+		got, err := repo.GetSession(ctx, session.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetSession: %v", err)
+		}
+		if got.ID != session.ID || got.UserID != session.UserID {
+			t.Fatalf("GetSession returned %+v, want session %s", got, session.ID.Hex())
+		}
+
+		_, err = repo.GetSession(ctx, bson.NewObjectID().Hex())
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+		if _, err := repo.GetSession(ctx, "not-an-object-id"); err == nil {
+			t.Fatal("expected an error for a malformed ID")
+		}
+	})
+
+	t.Run("GetSessionByUserID looks sessions up by user ID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		mustCreateSession(t, repo, bson.NewObjectID().Hex())
+		mustCreateSession(t, repo, userID)
+		latest := mustCreateSession(t, repo, userID)
+
+		got, err := repo.GetSessionByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetSessionByUserID: %v", err)
+		}
+		if got.ID != latest.ID {
+			t.Fatalf("GetSessionByUserID returned session %s, want latest session %s", got.ID.Hex(), latest.ID.Hex())
+		}
+
+		_, err = repo.GetSessionByUserID(ctx, bson.NewObjectID().Hex())
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+	})
+
+	t.Run("UpdateTokens persists the tokens", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		session := mustCreateSession(t, repo, userID)
+
+		expiresAt := time.Now().Add(time.Hour)
+		params := domain.UpdateTokensParams{
+			AccessToken:           "access-token",
+			RefreshToken:          "refresh-token",
+			AccessTokenExpiresAt:  expiresAt,
+			RefreshTokenExpiresAt: expiresAt.Add(time.Hour),
+		}
+
+		updated, err := repo.UpdateTokens(ctx, session.ID.Hex(), params)
+		if err != nil {
+			t.Fatalf("UpdateTokens: %v", err)
+		}
+		if updated.AccessToken != params.AccessToken || updated.RefreshToken != params.RefreshToken {
+			t.Fatalf("UpdateTokens returned %+v, want the updated tokens", updated)
+		}
+
+		stored, err := repo.GetSessionByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetSessionByUserID: %v", err)
+		}
+		if stored.AccessToken != params.AccessToken || stored.RefreshToken != params.RefreshToken {
+			t.Fatalf("stored session has tokens %q/%q, want %q/%q",
+				stored.AccessToken, stored.RefreshToken, params.AccessToken, params.RefreshToken)
+		}
+		if !stored.AccessTokenExpiresAt.Equal(expiresAt.Truncate(time.Millisecond)) {
+			t.Fatalf("stored access token expiry is %v, want %v", stored.AccessTokenExpiresAt, expiresAt)
+		}
+	})
+
+	t.Run("UpdateTokens swaps only the previous refresh token", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		session := mustCreateSession(t, repo, bson.NewObjectID().Hex())
+		if _, err := repo.UpdateTokens(ctx, session.ID.Hex(), domain.UpdateTokensParams{
+			RefreshToken: "first",
+		}); err != nil {
+			t.Fatalf("UpdateTokens: %v", err)
+		}
+
+		swap := func(previous, next string) error {
+			_, err := repo.UpdateTokens(ctx, session.ID.Hex(), domain.UpdateTokensParams{
+				RefreshToken:         next,
+				PreviousRefreshToken: previous,
+			})
+			return err
+		}
+		if err := swap("first", "second"); err != nil {
+			t.Fatalf("swapping the current refresh token: %v", err)
+		}
+		if err := swap("first", "third"); !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("swapping a replaced refresh token returned %v, want ErrNoDocuments", err)
+		}
+
+		stored, err := repo.GetSession(ctx, session.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetSession: %v", err)
+		}
+		if stored.RefreshToken != "second" {
+			t.Fatalf("stored refresh token is %q, want %q", stored.RefreshToken, "second")
+		}
+	})
+
+	t.Run("UpdateTokens reports missing and malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.UpdateTokens(ctx, bson.NewObjectID().Hex(), domain.UpdateTokensParams{AccessToken: "token"})
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+		if _, err := repo.UpdateTokens(ctx, "not-an-object-id", domain.UpdateTokensParams{}); err == nil {
+			t.Fatal("expected an error for a malformed ID")
+		}
+	})
+
+	t.Run("ListSessionsByUserID returns the user's sessions newest first", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		first := mustCreateSession(t, repo, userID)
+		second := mustCreateSession(t, repo, userID)
+		mustCreateSession(t, repo, bson.NewObjectID().Hex())
+
+		sessions, err := repo.ListSessionsByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("ListSessionsByUserID: %v", err)
+		}
+		if len(sessions) != 2 || sessions[0].ID != second.ID || sessions[1].ID != first.ID {
+			t.Fatalf("ListSessionsByUserID returned %d sessions in the wrong order", len(sessions))
+		}
+	})
+
+	t.Run("RevokeSession marks the session revoked", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		session := mustCreateSession(t, repo, userID)
+
+		if err := repo.RevokeSession(ctx, session.ID.Hex()); err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+
+		stored, err := repo.GetSessionByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetSessionByUserID: %v", err)
+		}
+		if stored.RevokedAt == nil {
+			t.Fatal("expected session to be revoked")
+		}
+	})
+
+	t.Run("CountActiveSessions skips revoked and expired sessions", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		for _, expiresAt := range []time.Time{time.Now().Add(time.Hour), time.Now().Add(-time.Hour)} {
+			if _, err := repo.CreateSession(ctx, &domain.Session{
+				UserID:                userID,
+				RefreshTokenExpiresAt: expiresAt,
+			}); err != nil {
+				t.Fatalf("CreateSession: %v", err)
+			}
+		}
+
+		revoked, err := repo.CreateSession(ctx, &domain.Session{
+			UserID:                userID,
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+		if err := repo.RevokeSession(ctx, revoked.ID.Hex()); err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+
+		count, err := repo.CountActiveSessions(ctx)
+		if err != nil {
+			t.Fatalf("CountActiveSessions: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("CountActiveSessions returned %d, want 1", count)
+		}
+	})
+}
+
+// RunIdentityRepositoryContract runs the domain.IdentityRepository contract against the factory's repositories.
+func RunIdentityRepositoryContract(t *testing.T, newRepo IdentityRepositoryFactory) {
+	t.Helper()
+
+	t.Run("GetIdentitiesByUserID looks identities up by user ID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		identity := mustCreateIdentity(t, repo, userID, "email", "")
+		mustCreateIdentity(t, repo, bson.NewObjectID().Hex(), "email", "")
+
+		identities, err := repo.GetIdentitiesByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetIdentitiesByUserID: %v", err)
+		}
+		if len(identities) != 1 || identities[0].ID != identity.ID {
+			t.Fatalf("GetIdentitiesByUserID returned %d identities, want only %s", len(identities), identity.ID.Hex())
+		}
+	})
+
+	t.Run("GetIdentityByProvider finds the identity", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		identity := mustCreateIdentity(t, repo, bson.NewObjectID().Hex(), "google", "google-123")
+
+		got, err := repo.GetIdentityByProvider(ctx, "google-123", "google")
+		if err != nil {
+			t.Fatalf("GetIdentityByProvider: %v", err)
+		}
+		if got.ID != identity.ID {
+			t.Fatalf("GetIdentityByProvider returned %s, want %s", got.ID.Hex(), identity.ID.Hex())
+		}
+
+		if _, err := repo.GetIdentityByProvider(ctx, "google-123", "facebook"); !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Fatalf("expected ErrNoDocuments, got %v", err)
+		}
+	})
+
+	t.Run("UpdateLastLogin updates the user's identities", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		userID := bson.NewObjectID().Hex()
+		mustCreateIdentity(t, repo, userID, "email", "")
+
+		before := time.Now().Add(-time.Second)
+		if err := repo.UpdateLastLogin(ctx, userID); err != nil {
+			t.Fatalf("UpdateLastLogin: %v", err)
+		}
+
+		identities, err := repo.GetIdentitiesByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetIdentitiesByUserID: %v", err)
+		}
+		if len(identities) != 1 || identities[0].LastLoginAt.Before(before) {
+			t.Fatal("expected last login time to be updated")
+		}
+	})
+}
+
+func mustCreateUser(t *testing.T, repo domain.UserRepository, email, fullName string) *domain.User {
+	t.Helper()
+
+	user, err := repo.CreateUser(context.Background(), &domain.User{Email: email, FullName: fullName})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return user
+}
+
+func mustCreateSession(t *testing.T, repo domain.SessionRepository, userID string) *domain.Session {
+	t.Helper()
+
+	session, err := repo.CreateSession(context.Background(), &domain.Session{UserID: userID})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if session.ID.IsZero() {
+		t.Fatal("expected session ID to be assigned")
+	}
+
+	return session
+}
+
+func mustCreateIdentity(
+	t *testing.T,
+	repo domain.IdentityRepository,
+	userID, provider, providerID string,
+) *domain.Identity {
+	t.Helper()
+
+	identity, err := repo.CreateIdentity(context.Background(), &domain.Identity{
+		UserID:     userID,
+		Provider:   provider,
+		ProviderID: providerID,
+		Email:      "alice@example.com",
+	})
+	if err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+
+	return identity
+}
+
+// roundTrip encodes and decodes the cursor, as it is when a client sends it back.
+func roundTrip(t *testing.T, cursor *contract.Cursor) *contract.Cursor {
+	t.Helper()
+
+	codec := contract.NewCursorCodec("secret")
+	token, err := codec.Encode(*cursor)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	return decoded
+}
+
+func assertEmails(t *testing.T, users []*domain.User, want ...string) {
+	t.Helper()
+
+	got := make([]string, 0, len(users))
+	for _, user := range users {
+		got = append(got, user.Email)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got users %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got users %v, want %v", got, want)
+		}
+	}
+}
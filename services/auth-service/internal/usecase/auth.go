@@ -2,45 +2,74 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/config"
 	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
 	authtypes "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/types"
 	"github.com/vasapolrittideah/moneylog-api/shared/auth"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/mail"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
 	"github.com/vasapolrittideah/moneylog-api/shared/security"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
 )
 
 type authUsecase struct {
-	identityRepo   domain.IdentityRepository
-	sessionRepo    domain.SessionRepository
-	userRepo       domain.UserRepository
-	authenticator  auth.Authenticator
-	authServiceCfg *config.AuthServiceConfig
+	identityRepo      domain.IdentityRepository
+	sessionRepo       domain.SessionRepository
+	userRepo          domain.UserRepository
+	authEventRepo     domain.AuthEventRepository
+	eventBroker       domain.AuthEventBroker
+	knownDeviceRepo   domain.KnownDeviceRepository
+	loginAlertRepo    domain.LoginAlertRepository
+	passwordResetRepo domain.PasswordResetRepository
+	authenticator     auth.Authenticator
+	mailer            mail.Mailer
+	cursors           *contract.CursorCodec
+	authServiceCfg    *config.AuthServiceConfig
 }
 
 func NewAuthUsecase(
 	identityRepo domain.IdentityRepository,
 	sessionRepo domain.SessionRepository,
 	userRepo domain.UserRepository,
+	authEventRepo domain.AuthEventRepository,
+	eventBroker domain.AuthEventBroker,
+	knownDeviceRepo domain.KnownDeviceRepository,
+	loginAlertRepo domain.LoginAlertRepository,
+	passwordResetRepo domain.PasswordResetRepository,
 	authenticator auth.Authenticator,
+	mailer mail.Mailer,
 	authServiceCfg *config.AuthServiceConfig,
 ) domain.AuthUsecase {
 	return &authUsecase{
-		identityRepo:   identityRepo,
-		sessionRepo:    sessionRepo,
-		userRepo:       userRepo,
-		authenticator:  authenticator,
-		authServiceCfg: authServiceCfg,
+		identityRepo:      identityRepo,
+		sessionRepo:       sessionRepo,
+		userRepo:          userRepo,
+		authEventRepo:     authEventRepo,
+		eventBroker:       eventBroker,
+		knownDeviceRepo:   knownDeviceRepo,
+		loginAlertRepo:    loginAlertRepo,
+		passwordResetRepo: passwordResetRepo,
+		authenticator:     authenticator,
+		mailer:            mailer,
+		cursors:           contract.NewCursorCodec(authServiceCfg.Pagination.CursorSecret),
+		authServiceCfg:    authServiceCfg,
 	}
 }
 
@@ -48,6 +77,13 @@ func (u *authUsecase) Login(ctx context.Context, params domain.LoginParams) (*au
 	user, err := u.userRepo.GetUserByEmail(ctx, params.Email)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
+			u.recordEvent(ctx, &domain.AuthEvent{
+				Type:      domain.AuthEventLoginFailed,
+				Email:     params.Email,
+				IPAddress: optionalString(params.IPAddress),
+				UserAgent: optionalString(params.UserAgent),
+				Reason:    ErrUserNotFound.Error(),
+			})
 			return nil, ErrUserNotFound
 		}
 
@@ -57,14 +93,50 @@ func (u *authUsecase) Login(ctx context.Context, params domain.LoginParams) (*au
 	if ok, err := security.VerifyPassword(params.Password, user.PasswordHash); err != nil {
 		return nil, err
 	} else if !ok {
+		u.recordEvent(ctx, &domain.AuthEvent{
+			UserID:    user.ID.Hex(),
+			Type:      domain.AuthEventLoginFailed,
+			Email:     params.Email,
+			IPAddress: optionalString(params.IPAddress),
+			UserAgent: optionalString(params.UserAgent),
+			Reason:    ErrInvalidCredentials.Error(),
+		})
 		return nil, ErrInvalidCredentials
 	}
 
+	if user.PasswordResetRequired {
+		u.recordEvent(ctx, &domain.AuthEvent{
+			UserID:    user.ID.Hex(),
+			Type:      domain.AuthEventLoginFailed,
+			Email:     params.Email,
+			IPAddress: optionalString(params.IPAddress),
+			UserAgent: optionalString(params.UserAgent),
+			Reason:    ErrPasswordResetRequired.Error(),
+		})
+		return nil, ErrPasswordResetRequired
+	}
+
 	if err := u.identityRepo.UpdateLastLogin(ctx, user.ID.Hex()); err != nil {
 		return nil, err
 	}
 
-	return u.createAuthSession(ctx, user.ID.Hex())
+	session, tokens, err := u.createAuthSession(ctx, user, params.IPAddress, params.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    user.ID.Hex(),
+		Type:      domain.AuthEventLoginSucceeded,
+		Email:     user.Email,
+		SessionID: session.ID.Hex(),
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+	})
+
+	go u.checkKnownDevice(context.WithoutCancel(ctx), *user, *session)
+
+	return tokens, nil
 }
 
 func (u *authUsecase) SignUp(ctx context.Context, params domain.SignUpParams) (*authtypes.Tokens, error) {
@@ -95,18 +167,191 @@ func (u *authUsecase) SignUp(ctx context.Context, params domain.SignUpParams) (*
 		return nil, err
 	}
 
-	return u.createAuthSession(ctx, user.ID.Hex())
+	session, tokens, err := u.createAuthSession(ctx, user, params.IPAddress, params.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    user.ID.Hex(),
+		Type:      domain.AuthEventSignUp,
+		Email:     user.Email,
+		SessionID: session.ID.Hex(),
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+	})
+
+	go u.checkKnownDevice(context.WithoutCancel(ctx), *user, *session)
+
+	return tokens, nil
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens for the same session. Refresh
+// tokens are single use: presenting one that has already been exchanged revokes the session, since
+// it means that the token was stolen.
+func (u *authUsecase) RefreshToken(ctx context.Context, params domain.RefreshTokenParams) (*authtypes.Tokens, error) {
+	token, err := u.authenticator.ValidateToken(params.RefreshToken, u.authServiceCfg.Token.RefreshTokenSecret)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+	userID, _ := claims["user_id"].(string)
+	sessionID, _ := claims["session_id"].(string)
+
+	session, err := u.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	if session.UserID != userID || session.RevokedAt != nil || time.Now().After(session.RefreshTokenExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(session.RefreshToken), []byte(params.RefreshToken)) != 1 {
+		logger.FromContext(ctx).Warn().Str("session_id", sessionID).Msg("Refresh token reused, revoking session")
+		if err := u.sessionRepo.RevokeSession(ctx, sessionID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := u.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	tokens, err := u.issueTokens(ctx, user, sessionID, params.RefreshToken)
+	if err != nil {
+		// Another request exchanged the same refresh token between the check above and the swap.
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    userID,
+		Type:      domain.AuthEventTokenRefreshed,
+		SessionID: sessionID,
+		IPAddress: optionalString(params.IPAddress),
+		UserAgent: optionalString(params.UserAgent),
+	})
+
+	return tokens, nil
+}
+
+// Logout revokes the session an access token was issued for, so that its refresh token can no
+// longer be exchanged. Signing out of a session that is already revoked succeeds without recording
+// another event.
+func (u *authUsecase) Logout(ctx context.Context, params domain.LogoutParams) error {
+	session, err := u.sessionRepo.GetSession(ctx, params.SessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			return ErrSessionNotFound
+		}
+
+		return err
+	}
+
+	if session.UserID != params.UserID {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := u.sessionRepo.RevokeSession(ctx, params.SessionID); err != nil {
+		return err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    params.UserID,
+		Type:      domain.AuthEventLogout,
+		SessionID: params.SessionID,
+		IPAddress: optionalString(params.IPAddress),
+		UserAgent: optionalString(params.UserAgent),
+	})
+
+	return nil
 }
 
-func (u *authUsecase) createAuthSession(ctx context.Context, userID string) (*authtypes.Tokens, error) {
-	session, err := u.sessionRepo.CreateSession(ctx, &domain.Session{UserID: userID})
+func (u *authUsecase) ListSecurityEvents(
+	ctx context.Context,
+	params domain.ListSecurityEventsParams,
+) (*domain.SecurityEventPage, error) {
+	filter := domain.FilterAuthEventParams{
+		Limit: params.Limit,
+	}
+	if params.Cursor != "" {
+		after, err := u.cursors.Decode(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	events, next, err := u.authEventRepo.ListAuthEventsByUserID(ctx, params.UserID, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	page := &domain.SecurityEventPage{Events: events}
+	if next != nil {
+		if page.NextCursor, err = u.cursors.Encode(*next); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+func (u *authUsecase) createAuthSession(
+	ctx context.Context,
+	user *domain.User,
+	ipAddress string,
+	userAgent string,
+) (*domain.Session, *authtypes.Tokens, error) {
+	session, err := u.sessionRepo.CreateSession(ctx, &domain.Session{
+		UserID:    user.ID.Hex(),
+		IPAddress: optionalString(ipAddress),
+		UserAgent: optionalString(userAgent),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := u.issueTokens(ctx, user, session.ID.Hex(), "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, tokens, nil
+}
+
+// issueTokens generates a new pair of tokens for the session and stores them on it. When
+// previousRefreshToken is set, the tokens replace it only if the session still holds it.
+func (u *authUsecase) issueTokens(
+	ctx context.Context,
+	user *domain.User,
+	sessionID, previousRefreshToken string,
+) (*authtypes.Tokens, error) {
 	accessToken, err := u.generateToken(
-		userID,
-		session.ID.Hex(),
+		user,
+		sessionID,
 		u.authServiceCfg.Token.AccessTokenSecret,
 		u.authServiceCfg.Token.AccessTokenExpiresIn,
 	)
@@ -115,8 +360,8 @@ func (u *authUsecase) createAuthSession(ctx context.Context, userID string) (*au
 	}
 
 	refreshToken, err := u.generateToken(
-		userID,
-		session.ID.Hex(),
+		user,
+		sessionID,
 		u.authServiceCfg.Token.RefreshTokenSecret,
 		u.authServiceCfg.Token.RefreshTokenExpiresIn,
 	)
@@ -125,11 +370,12 @@ func (u *authUsecase) createAuthSession(ctx context.Context, userID string) (*au
 	}
 
 	now := time.Now()
-	if _, err := u.sessionRepo.UpdateTokens(ctx, session.ID.Hex(), domain.UpdateTokensParams{
+	if _, err := u.sessionRepo.UpdateTokens(ctx, sessionID, domain.UpdateTokensParams{
 		AccessToken:           accessToken,
 		RefreshToken:          refreshToken,
 		AccessTokenExpiresAt:  now.Add(u.authServiceCfg.Token.AccessTokenExpiresIn),
 		RefreshTokenExpiresAt: now.Add(u.authServiceCfg.Token.RefreshTokenExpiresIn),
+		PreviousRefreshToken:  previousRefreshToken,
 	}); err != nil {
 		return nil, err
 	}
@@ -140,12 +386,22 @@ func (u *authUsecase) createAuthSession(ctx context.Context, userID string) (*au
 	}, nil
 }
 
-func (u *authUsecase) generateToken(userID, sessionID, secret string, expiresIn time.Duration) (string, error) {
+// generateToken signs a token for the user's session. It carries the locale of the user's profile,
+// so that the gateway can localize responses without looking the profile up.
+func (u *authUsecase) generateToken(
+	user *domain.User,
+	sessionID, secret string,
+	expiresIn time.Duration,
+) (string, error) {
 	now := time.Now()
 	claims := authtypes.JWTClaims{
-		UserID:    userID,
+		UserID:    user.ID.Hex(),
 		SessionID: sessionID,
+		Locale:    user.Locale,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// The ID keeps tokens issued within the same second distinct, so that a rotated refresh
+			// token never equals the one it replaces.
+			ID:        uuid.NewString(),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
 			NotBefore: jwt.NewNumericDate(now),
@@ -160,3 +416,49 @@ func (u *authUsecase) generateToken(userID, sessionID, secret string, expiresIn
 
 	return token, nil
 }
+
+// recordEvent appends an event to the security audit log, which the event brokers of every instance
+// tail, and counts it in the metrics. Failing to record an event is logged but does not fail the
+// authentication flow that triggered it.
+func (u *authUsecase) recordEvent(ctx context.Context, event *domain.AuthEvent) {
+	switch event.Type {
+	case domain.AuthEventSignUp:
+		metrics.RecordSignUp()
+	case domain.AuthEventLoginSucceeded:
+		metrics.RecordLogin()
+	case domain.AuthEventLoginFailed:
+		metrics.RecordFailedLogin()
+	}
+
+	if _, err := u.authEventRepo.CreateAuthEvent(ctx, event); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Str("eventType", string(event.Type)).Msg("Failed to record auth event")
+	}
+}
+
+// revokeSessions revokes every active session of the user except the one with exceptSessionID,
+// which may be empty to revoke them all.
+func (u *authUsecase) revokeSessions(ctx context.Context, userID string, exceptSessionID string) error {
+	sessions, err := u.sessionRepo.ListSessionsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.RevokedAt != nil || session.ID.Hex() == exceptSessionID {
+			continue
+		}
+		if err := u.sessionRepo.RevokeSession(ctx, session.ID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,583 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/config"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/memory"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/usecase"
+	authtypes "github.com/vasapolrittideah/moneylog-api/services/auth-service/pkg/types"
+	"github.com/vasapolrittideah/moneylog-api/shared/auth"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/mail"
+	"github.com/vasapolrittideah/moneylog-api/shared/security"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// auditLog is an audit log kept in memory. It can be shared by several instances of the use cases.
+type auditLog struct {
+	mu     sync.Mutex
+	events []domain.AuthEvent
+}
+
+func (l *auditLog) CreateAuthEvent(_ context.Context, event *domain.AuthEvent) (*domain.AuthEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.ID = bson.NewObjectID()
+	event.CreatedAt = time.Now()
+	l.events = append(l.events, *event)
+
+	return event, nil
+}
+
+func (l *auditLog) ListAuthEventsByUserID(
+	context.Context,
+	string,
+	domain.FilterAuthEventParams,
+) ([]domain.AuthEvent, *contract.Cursor, error) {
+	return nil, nil, nil
+}
+
+func (l *auditLog) ListAuthEventsAfter(
+	_ context.Context,
+	userID string,
+	afterID string,
+	limit uint64,
+) ([]domain.AuthEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []domain.AuthEvent
+	for _, event := range l.events {
+		if event.UserID == userID && event.ID.Hex() > afterID && uint64(len(events)) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (l *auditLog) ListAuthEventsSince(
+	_ context.Context,
+	since time.Time,
+	_ uint64,
+	_ *contract.Cursor,
+) ([]domain.AuthEvent, *contract.Cursor, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []domain.AuthEvent
+	for _, event := range l.events {
+		if !event.CreatedAt.Before(since) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil, nil
+}
+
+// knownDevices remembers no device, so that every login is the user's first.
+type knownDevices struct{}
+
+func (knownDevices) GetKnownDevicesByUserID(context.Context, string) ([]domain.KnownDevice, error) {
+	return nil, nil
+}
+
+func (knownDevices) UpsertKnownDevice(context.Context, *domain.KnownDevice) error {
+	return nil
+}
+
+// mailbox delivers the messages sent to it on a channel.
+type mailbox chan mail.Message
+
+func (m mailbox) Send(_ context.Context, msg mail.Message) error {
+	m <- msg
+	return nil
+}
+
+// receive waits for the next message and returns the token of the link it holds.
+func (m mailbox) receive(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case msg := <-m:
+		for _, field := range strings.Fields(msg.Body) {
+			if link, err := url.Parse(field); err == nil && link.Query().Has("token") {
+				return link.Query().Get("token")
+			}
+		}
+		t.Fatalf("message %q holds no link with a token", msg.Body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message was sent")
+	}
+
+	return ""
+}
+
+// racingSessions exchanges the refresh token of a session right before a refresh swaps it, as a
+// concurrent request with the same token would, while race is set.
+type racingSessions struct {
+	domain.SessionRepository
+
+	race bool
+}
+
+func (r *racingSessions) UpdateTokens(
+	ctx context.Context,
+	id string,
+	params domain.UpdateTokensParams,
+) (*domain.Session, error) {
+	if r.race && params.PreviousRefreshToken != "" {
+		if _, err := r.SessionRepository.UpdateTokens(ctx, id, domain.UpdateTokensParams{
+			RefreshToken:         "exchanged",
+			PreviousRefreshToken: params.PreviousRefreshToken,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.SessionRepository.UpdateTokens(ctx, id, params)
+}
+
+type testAuth struct {
+	usecase     domain.AuthUsecase
+	users       domain.UserRepository
+	sessions    domain.SessionRepository
+	events      *auditLog
+	loginAlerts domain.LoginAlertRepository
+	mailbox     mailbox
+	stopBroker  context.CancelFunc
+}
+
+func newTestAuth(t *testing.T) *testAuth {
+	t.Helper()
+
+	return newTestAuthWithSessions(t, memory.NewSessionRepository())
+}
+
+func newTestAuthWithSessions(t *testing.T, sessions domain.SessionRepository) *testAuth {
+	t.Helper()
+
+	a := &testAuth{
+		users:       memory.NewUserRepository(),
+		sessions:    sessions,
+		events:      &auditLog{},
+		loginAlerts: memory.NewLoginAlertRepository(),
+		mailbox:     make(mailbox, 10),
+	}
+	a.start(t)
+
+	return a
+}
+
+// replica returns another instance of the use cases that shares the repositories of a.
+func (a *testAuth) replica(t *testing.T) *testAuth {
+	t.Helper()
+
+	replica := *a
+	replica.start(t)
+
+	return &replica
+}
+
+// start creates the use cases with a broker of their own.
+func (a *testAuth) start(t *testing.T) {
+	t.Helper()
+
+	brokerCtx, stopBroker := context.WithCancel(t.Context())
+	a.stopBroker = stopBroker
+	a.usecase = usecase.NewAuthUsecase(
+		memory.NewIdentityRepository(),
+		a.sessions,
+		a.users,
+		a.events,
+		usecase.NewAuthEventBroker(brokerCtx, a.events, 10*time.Millisecond),
+		knownDevices{},
+		a.loginAlerts,
+		memory.NewPasswordResetRepository(),
+		auth.NewJWTAuthenticator("auth-service", "auth-service"),
+		a.mailbox,
+		&config.AuthServiceConfig{
+			Token: config.TokenConfig{
+				AccessTokenSecret:     "access-secret",
+				RefreshTokenSecret:    "refresh-secret",
+				AccessTokenExpiresIn:  time.Minute,
+				RefreshTokenExpiresIn: time.Hour,
+				Issuer:                "auth-service",
+			},
+			Notification: config.NotificationConfig{
+				LoginAlertURL:    "https://moneylog.test/login-alerts/revoke",
+				PasswordResetURL: "https://moneylog.test/password-reset",
+			},
+			Pagination: config.PaginationConfig{CursorSecret: "cursor-secret"},
+		},
+	)
+}
+
+func (a *testAuth) signUp(t *testing.T) *authtypes.Tokens {
+	t.Helper()
+
+	tokens, err := a.usecase.SignUp(context.Background(), domain.SignUpParams{
+		Email:    "ada@example.com",
+		Password: "old-password",
+		FullName: "Ada Lovelace",
+	})
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+
+	return tokens
+}
+
+func (a *testAuth) refresh(refreshToken string) (*authtypes.Tokens, error) {
+	return a.usecase.RefreshToken(context.Background(), domain.RefreshTokenParams{RefreshToken: refreshToken})
+}
+
+func (a *testAuth) login(password string) error {
+	_, err := a.usecase.Login(context.Background(), domain.LoginParams{
+		Email:    "ada@example.com",
+		Password: password,
+	})
+	return err
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAuth(t)
+
+	a.signUp(t)
+	user, err := a.users.GetUserByEmail(ctx, "ada@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	session, err := a.sessions.GetSessionByUserID(ctx, user.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetSessionByUserID: %v", err)
+	}
+
+	alertToken, err := security.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatalf("GenerateOpaqueToken: %v", err)
+	}
+	if _, err := a.loginAlerts.CreateLoginAlert(ctx, &domain.LoginAlert{
+		UserID:    user.ID.Hex(),
+		SessionID: session.ID.Hex(),
+		TokenHash: security.HashOpaqueToken(alertToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("CreateLoginAlert: %v", err)
+	}
+
+	if err := a.usecase.RevokeSuspiciousLogin(ctx, alertToken); err != nil {
+		t.Fatalf("RevokeSuspiciousLogin: %v", err)
+	}
+	if err := a.login("old-password"); !errors.Is(err, usecase.ErrPasswordResetRequired) {
+		t.Fatalf("Login after a reported login returned %v, want %v", err, usecase.ErrPasswordResetRequired)
+	}
+
+	if err := a.usecase.RequestPasswordReset(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset of an unknown email: %v", err)
+	}
+	if err := a.usecase.RequestPasswordReset(ctx, "ada@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	resetToken := a.mailbox.receive(t)
+	if len(a.mailbox) != 0 {
+		t.Fatalf("%d more messages were sent, want one for the known email only", len(a.mailbox))
+	}
+
+	if err := a.usecase.ResetPassword(ctx, domain.ResetPasswordParams{
+		Token:       resetToken,
+		NewPassword: "new-password",
+	}); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := a.login("new-password"); err != nil {
+		t.Fatalf("Login with the new password: %v", err)
+	}
+	if err := a.login("old-password"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Fatalf("Login with the old password returned %v, want %v", err, usecase.ErrInvalidCredentials)
+	}
+
+	sessions, err := a.sessions.ListSessionsByUserID(ctx, user.ID.Hex())
+	if err != nil {
+		t.Fatalf("ListSessionsByUserID: %v", err)
+	}
+	for _, s := range sessions {
+		if s.ID == session.ID && s.RevokedAt == nil {
+			t.Errorf("session %s from before the reset is still active", s.ID.Hex())
+		}
+	}
+
+	for _, token := range []string{resetToken, "unknown"} {
+		if err := a.usecase.ResetPassword(ctx, domain.ResetPasswordParams{
+			Token:       token,
+			NewPassword: "another-password",
+		}); !errors.Is(err, usecase.ErrInvalidPasswordReset) {
+			t.Errorf("ResetPassword with token %q returned %v, want %v", token, err, usecase.ErrInvalidPasswordReset)
+		}
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		a := newTestAuth(t)
+		first := a.signUp(t)
+
+		second, err := a.refresh(first.RefreshToken)
+		if err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		if second.RefreshToken == first.RefreshToken {
+			t.Fatal("RefreshToken returned the refresh token it was given")
+		}
+		if _, err := a.refresh(second.RefreshToken); err != nil {
+			t.Fatalf("RefreshToken with the rotated token: %v", err)
+		}
+	})
+
+	t.Run("revokes the session when a refresh token is reused", func(t *testing.T) {
+		a := newTestAuth(t)
+		first := a.signUp(t)
+
+		second, err := a.refresh(first.RefreshToken)
+		if err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		if _, err := a.refresh(first.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Fatalf("RefreshToken with a reused token returned %v, want %v", err, usecase.ErrInvalidRefreshToken)
+		}
+		if _, err := a.refresh(second.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Fatalf("RefreshToken after a reuse returned %v, want %v", err, usecase.ErrInvalidRefreshToken)
+		}
+	})
+
+	t.Run("rejects a refresh token exchanged during the refresh", func(t *testing.T) {
+		sessions := &racingSessions{SessionRepository: memory.NewSessionRepository()}
+		a := newTestAuthWithSessions(t, sessions)
+		tokens := a.signUp(t)
+
+		sessions.race = true
+		if _, err := a.refresh(tokens.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Fatalf("RefreshToken that lost the swap returned %v, want %v", err, usecase.ErrInvalidRefreshToken)
+		}
+	})
+}
+
+// session returns the user's only session.
+func (a *testAuth) session(t *testing.T) *domain.Session {
+	t.Helper()
+
+	user, err := a.users.GetUserByEmail(context.Background(), "ada@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	session, err := a.sessions.GetSessionByUserID(context.Background(), user.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetSessionByUserID: %v", err)
+	}
+
+	return session
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAuth(t)
+	tokens := a.signUp(t)
+	session := a.session(t)
+
+	params := domain.LogoutParams{UserID: "another-user", SessionID: session.ID.Hex()}
+	if err := a.usecase.Logout(ctx, params); !errors.Is(err, usecase.ErrSessionNotFound) {
+		t.Fatalf("Logout of another user's session returned %v, want %v", err, usecase.ErrSessionNotFound)
+	}
+
+	params.UserID = session.UserID
+	if err := a.usecase.Logout(ctx, params); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := a.refresh(tokens.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Fatalf("RefreshToken after logout returned %v, want %v", err, usecase.ErrInvalidRefreshToken)
+	}
+	if err := a.usecase.Logout(ctx, params); err != nil {
+		t.Fatalf("Logout of a revoked session: %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAuth(t)
+	a.signUp(t)
+	session := a.session(t)
+
+	other, err := a.usecase.Login(ctx, domain.LoginParams{Email: "ada@example.com", Password: "old-password"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	params := domain.ChangePasswordParams{
+		UserID:          session.UserID,
+		SessionID:       session.ID.Hex(),
+		CurrentPassword: "wrong-password",
+		NewPassword:     "new-password",
+	}
+	if err := a.usecase.ChangePassword(ctx, params); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Fatalf("ChangePassword with a wrong password returned %v, want %v", err, usecase.ErrInvalidCredentials)
+	}
+
+	params.CurrentPassword = "old-password"
+	if err := a.usecase.ChangePassword(ctx, params); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if err := a.login("new-password"); err != nil {
+		t.Fatalf("Login with the new password: %v", err)
+	}
+	if err := a.login("old-password"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Fatalf("Login with the old password returned %v, want %v", err, usecase.ErrInvalidCredentials)
+	}
+
+	if _, err := a.refresh(other.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken of another session returned %v, want %v", err, usecase.ErrInvalidRefreshToken)
+	}
+	current, err := a.sessions.GetSession(ctx, session.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if current.RevokedAt != nil {
+		t.Error("the session the password was changed from was revoked")
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	t.Run("ends when the broker stops", func(t *testing.T) {
+		a := newTestAuth(t)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- a.usecase.SubscribeEvents(
+				context.Background(),
+				domain.SubscribeEventsParams{UserID: "u1"},
+				func(domain.AuthEvent) error { return nil },
+			)
+		}()
+		a.stopBroker()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("SubscribeEvents: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("SubscribeEvents is still running after the broker stopped")
+		}
+	})
+
+	t.Run("delivers the events recorded by another instance", func(t *testing.T) {
+		a := newTestAuth(t)
+		a.signUp(t)
+		session := a.session(t)
+		if err := a.login("old-password"); err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		a.events.mu.Lock()
+		signUpEvent := a.events.events[0]
+		a.events.mu.Unlock()
+		replica := a.replica(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		received := make(chan domain.AuthEvent, 10)
+		go func() {
+			_ = replica.usecase.SubscribeEvents(
+				ctx,
+				domain.SubscribeEventsParams{UserID: session.UserID, LastEventID: signUpEvent.ID.Hex()},
+				func(event domain.AuthEvent) error {
+					received <- event
+					return nil
+				},
+			)
+		}()
+
+		// The login is replayed once the subscription is open.
+		receive := func(want domain.AuthEventType) {
+			t.Helper()
+
+			select {
+			case event := <-received:
+				if event.Type != want {
+					t.Fatalf("received a %s event, want %s", event.Type, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no %s event was delivered", want)
+			}
+		}
+		receive(domain.AuthEventLoginSucceeded)
+
+		params := domain.LogoutParams{UserID: session.UserID, SessionID: session.ID.Hex()}
+		if err := a.usecase.Logout(ctx, params); err != nil {
+			t.Fatalf("Logout: %v", err)
+		}
+		receive(domain.AuthEventLogout)
+	})
+
+	t.Run("delivers events inserted out of ID order", func(t *testing.T) {
+		a := newTestAuth(t)
+		a.signUp(t)
+		session := a.session(t)
+		if err := a.login("old-password"); err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		a.events.mu.Lock()
+		signUpEvent := a.events.events[0]
+		a.events.mu.Unlock()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		received := make(chan domain.AuthEvent, 10)
+		go func() {
+			_ = a.usecase.SubscribeEvents(
+				ctx,
+				domain.SubscribeEventsParams{UserID: session.UserID, LastEventID: signUpEvent.ID.Hex()},
+				func(event domain.AuthEvent) error {
+					received <- event
+					return nil
+				},
+			)
+		}()
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the login was not replayed")
+		}
+
+		// An event whose ID was generated before the login's but that was inserted after it, as
+		// another goroutine or instance may do.
+		a.events.mu.Lock()
+		a.events.events = append(a.events.events, domain.AuthEvent{
+			ID:        bson.NewObjectIDFromTimestamp(signUpEvent.ID.Timestamp()),
+			UserID:    session.UserID,
+			Type:      domain.AuthEventNewDeviceLogin,
+			CreatedAt: time.Now(),
+		})
+		a.events.mu.Unlock()
+
+		select {
+		case event := <-received:
+			if event.Type != domain.AuthEventNewDeviceLogin {
+				t.Fatalf("received a %s event, want %s", event.Type, domain.AuthEventNewDeviceLogin)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the event inserted out of ID order was not delivered")
+		}
+	})
+}
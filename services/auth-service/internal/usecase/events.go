@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// eventBufferSize is the number of events a subscriber may fall behind by before it is dropped.
+	eventBufferSize = 64
+	// replayBatchSize is the number of missed events read from the audit log at a time.
+	replayBatchSize = 100
+	// tailBatchSize is the number of new events read from the audit log at a time.
+	tailBatchSize = 500
+	// tailLookback is how far before the newest event it has seen the broker reads the audit log
+	// again, so that events that were inserted late, or stamped by an instance whose clock lags,
+	// are still delivered.
+	tailLookback = 10 * time.Second
+)
+
+var (
+	ErrInvalidLastEventID = errors.New("invalid last event ID")
+	ErrSubscriberLagged   = errors.New("subscriber fell behind")
+)
+
+type authEventBroker struct {
+	ctx           context.Context
+	authEventRepo domain.AuthEventRepository
+	mu            sync.Mutex
+	subscribers   map[string]map[chan domain.AuthEvent]struct{}
+
+	// newest and seen are only used by the tail goroutine. seen holds the creation time of the
+	// events read within the lookback, so that reading them again does not deliver them twice.
+	newest time.Time
+	seen   map[bson.ObjectID]time.Time
+}
+
+// NewAuthEventBroker creates a broker that tails the audit log every pollInterval and delivers the
+// new events to the subscribers of this instance, until ctx, the lifetime of the service, is done.
+// The audit log is shared by every instance, so subscribers receive the events recorded by any of
+// them. Ending the subscriptions at shutdown lets the server stop gracefully; clients reconnect
+// and replay what they missed from the audit log.
+func NewAuthEventBroker(
+	ctx context.Context,
+	authEventRepo domain.AuthEventRepository,
+	pollInterval time.Duration,
+) domain.AuthEventBroker {
+	b := &authEventBroker{
+		ctx:           ctx,
+		authEventRepo: authEventRepo,
+		subscribers:   make(map[string]map[chan domain.AuthEvent]struct{}),
+		newest:        time.Now(),
+		seen:          make(map[bson.ObjectID]time.Time),
+	}
+	go b.tail(pollInterval)
+
+	return b
+}
+
+func (b *authEventBroker) Subscribe(userID string) (<-chan domain.AuthEvent, func()) {
+	events := make(chan domain.AuthEvent, eventBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan domain.AuthEvent]struct{})
+	}
+	b.subscribers[userID][events] = struct{}{}
+	b.mu.Unlock()
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[userID][events]; ok {
+			b.remove(userID, events)
+		}
+	}
+}
+
+func (b *authEventBroker) Done() <-chan struct{} {
+	return b.ctx.Done()
+}
+
+// tail polls the audit log every interval until the broker stops.
+func (b *authEventBroker) tail(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := b.poll(); err != nil && b.ctx.Err() == nil {
+			logger.FromContext(b.ctx).Error().Err(err).Msg("Failed to poll auth events")
+		}
+	}
+}
+
+// poll delivers the events recorded within the lookback of the newest event seen that have not
+// been delivered yet.
+func (b *authEventBroker) poll() error {
+	since := b.newest.Add(-tailLookback)
+
+	var after *contract.Cursor
+	for {
+		events, next, err := b.authEventRepo.ListAuthEventsSince(b.ctx, since, tailBatchSize, after)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if _, ok := b.seen[event.ID]; ok {
+				continue
+			}
+			b.seen[event.ID] = event.CreatedAt
+			if event.CreatedAt.After(b.newest) {
+				b.newest = event.CreatedAt
+			}
+			b.publish(event)
+		}
+
+		if next == nil {
+			break
+		}
+		after = next
+	}
+
+	// Events from before the lookback are never read again, so they need not be remembered.
+	since = b.newest.Add(-tailLookback)
+	for id, createdAt := range b.seen {
+		if createdAt.Before(since) {
+			delete(b.seen, id)
+		}
+	}
+
+	return nil
+}
+
+// publish delivers an event to the subscribers of its user.
+func (b *authEventBroker) publish(event domain.AuthEvent) {
+	if event.UserID == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers[event.UserID] {
+		select {
+		case events <- event:
+		default:
+			// Dropping the subscriber rather than the event lets it resubscribe from its last event
+			// without a gap.
+			b.remove(event.UserID, events)
+		}
+	}
+}
+
+// remove ends a subscription and closes its channel. Callers must hold the lock.
+func (b *authEventBroker) remove(userID string, events chan domain.AuthEvent) {
+	delete(b.subscribers[userID], events)
+	if len(b.subscribers[userID]) == 0 {
+		delete(b.subscribers, userID)
+	}
+	close(events)
+}
+
+// SubscribeEvents sends the user's security events to send as they are recorded, until ctx is done
+// or the broker stops. Events recorded after params.LastEventID are replayed from the audit log
+// first, so a client that reconnects misses none.
+func (u *authUsecase) SubscribeEvents(
+	ctx context.Context,
+	params domain.SubscribeEventsParams,
+	send func(domain.AuthEvent) error,
+) error {
+	// Subscribe before replaying, so that events recorded during the replay are not missed.
+	events, unsubscribe := u.eventBroker.Subscribe(params.UserID)
+	defer unsubscribe()
+
+	// The IDs of the events the client already has, which the broker may deliver again. Live events
+	// are not compared by ID order, since events recorded concurrently are not inserted in that order.
+	delivered := make(map[bson.ObjectID]struct{})
+	if params.LastEventID != "" {
+		lastID, err := bson.ObjectIDFromHex(params.LastEventID)
+		if err != nil {
+			return ErrInvalidLastEventID
+		}
+		delivered[lastID] = struct{}{}
+
+		for {
+			missed, err := u.authEventRepo.ListAuthEventsAfter(ctx, params.UserID, lastID.Hex(), replayBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, event := range missed {
+				if err := send(event); err != nil {
+					return err
+				}
+				delivered[event.ID] = struct{}{}
+				lastID = event.ID
+			}
+			if len(missed) < replayBatchSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-u.eventBroker.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return ErrSubscriberLagged
+			}
+			if _, ok := delivered[event.ID]; ok {
+				delete(delivered, event.ID)
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/export"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrExportJobNotFound = errors.New("export job not found")
+	ErrExportNotReady    = errors.New("export is not ready")
+)
+
+// exportTimeout bounds how long a single export job may run in the background.
+const exportTimeout = 5 * time.Minute
+
+type exportUsecase struct {
+	exportJobRepo domain.ExportJobRepository
+	userRepo      domain.UserRepository
+	registry      *export.Registry
+}
+
+func NewExportUsecase(
+	exportJobRepo domain.ExportJobRepository,
+	userRepo domain.UserRepository,
+	registry *export.Registry,
+) domain.ExportUsecase {
+	return &exportUsecase{
+		exportJobRepo: exportJobRepo,
+		userRepo:      userRepo,
+		registry:      registry,
+	}
+}
+
+func (u *exportUsecase) ExportUserData(ctx context.Context, userID string) (*domain.ExportJob, error) {
+	if _, err := u.userRepo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	job, err := u.exportJobRepo.CreateExportJob(ctx, &domain.ExportJob{
+		UserID: userID,
+		Status: domain.ExportStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go u.runExport(context.WithoutCancel(ctx), job.ID.Hex(), userID)
+
+	return job, nil
+}
+
+func (u *exportUsecase) GetExportJob(ctx context.Context, userID string, jobID string) (*domain.ExportJob, error) {
+	job, err := u.exportJobRepo.GetExportJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			return nil, ErrExportJobNotFound
+		}
+
+		return nil, err
+	}
+
+	// Jobs belonging to other users are reported as missing so their existence is not leaked.
+	if job.UserID != userID {
+		return nil, ErrExportJobNotFound
+	}
+
+	return job, nil
+}
+
+func (u *exportUsecase) DownloadExport(ctx context.Context, userID string, jobID string) (*domain.ExportJob, error) {
+	job, err := u.GetExportJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != domain.ExportStatusCompleted {
+		return nil, ErrExportNotReady
+	}
+
+	return job, nil
+}
+
+// runExport builds the archive for a job outside of the request lifecycle and records the outcome.
+func (u *exportUsecase) runExport(ctx context.Context, jobID, userID string) {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	running := domain.ExportStatusRunning
+	if _, err := u.exportJobRepo.UpdateExportJob(ctx, jobID, domain.UpdateExportJobParams{
+		Status: &running,
+	}); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Str("jobID", jobID).Msg("Failed to mark export job as running")
+		return
+	}
+
+	archive, err := u.registry.Build(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Error().Err(err).Str("jobID", jobID).Msg("Failed to build user data export")
+
+		failed := domain.ExportStatusFailed
+		message := "failed to build export archive"
+		if _, err := u.exportJobRepo.UpdateExportJob(ctx, jobID, domain.UpdateExportJobParams{
+			Status: &failed,
+			Error:  &message,
+		}); err != nil {
+			logger.FromContext(ctx).Error().Err(err).Str("jobID", jobID).Msg("Failed to mark export job as failed")
+		}
+		return
+	}
+
+	completed := domain.ExportStatusCompleted
+	completedAt := time.Now()
+	if _, err := u.exportJobRepo.UpdateExportJob(ctx, jobID, domain.UpdateExportJobParams{
+		Status:      &completed,
+		Archive:     archive,
+		CompletedAt: &completedAt,
+	}); err != nil {
+		logger.FromContext(ctx).Error().Err(err).Str("jobID", jobID).Msg("Failed to store user data export")
+	}
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/export"
+)
+
+// userExport is the exported view of a user. It deliberately omits the password hash
+// and the pending verification code.
+type userExport struct {
+	ID                    string    `json:"id"`
+	FullName              string    `json:"full_name"`
+	Email                 string    `json:"email"`
+	Verified              bool      `json:"verified"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	DisplayName           string    `json:"display_name,omitempty"`
+	AvatarURL             string    `json:"avatar_url,omitempty"`
+	Locale                string    `json:"locale,omitempty"`
+	Timezone              string    `json:"timezone,omitempty"`
+	DefaultCurrency       string    `json:"default_currency,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type identityExport struct {
+	ID          string    `json:"id"`
+	Provider    string    `json:"provider"`
+	ProviderID  string    `json:"provider_id,omitempty"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// sessionExport is the exported view of a session. Tokens are credentials rather than
+// personal data, so only their expiry times are included.
+type sessionExport struct {
+	ID                    string    `json:"id"`
+	IPAddress             *string   `json:"ip_address,omitempty"`
+	UserAgent             *string   `json:"user_agent,omitempty"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type authEventExport struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Email     string    `json:"email,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	IPAddress *string   `json:"ip_address,omitempty"`
+	UserAgent *string   `json:"user_agent,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type knownDeviceExport struct {
+	ID          string    `json:"id"`
+	Fingerprint string    `json:"fingerprint"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// loginAlertExport is the exported view of a login alert. The "this wasn't me" token hash is a
+// credential, so it is left out.
+type loginAlertExport struct {
+	ID        string     `json:"id"`
+	SessionID string     `json:"session_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type userExportProvider struct {
+	userRepo domain.UserRepository
+}
+
+// NewUserExportProvider creates an export provider for the user's account.
+func NewUserExportProvider(userRepo domain.UserRepository) export.Provider {
+	return &userExportProvider{userRepo: userRepo}
+}
+
+func (p *userExportProvider) Name() string {
+	return "user"
+}
+
+func (p *userExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	user, err := p.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return userExport{
+		ID:                    user.ID.Hex(),
+		FullName:              user.FullName,
+		Email:                 user.Email,
+		Verified:              user.Verified,
+		PasswordResetRequired: user.PasswordResetRequired,
+		DisplayName:           user.DisplayName,
+		AvatarURL:             user.AvatarURL,
+		Locale:                user.Locale,
+		Timezone:              user.Timezone,
+		DefaultCurrency:       user.DefaultCurrency,
+		CreatedAt:             user.CreatedAt,
+		UpdatedAt:             user.UpdatedAt,
+	}, nil
+}
+
+type identityExportProvider struct {
+	identityRepo domain.IdentityRepository
+}
+
+// NewIdentityExportProvider creates an export provider for the user's linked identities.
+func NewIdentityExportProvider(identityRepo domain.IdentityRepository) export.Provider {
+	return &identityExportProvider{identityRepo: identityRepo}
+}
+
+func (p *identityExportProvider) Name() string {
+	return "identities"
+}
+
+func (p *identityExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	identities, err := p.identityRepo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]identityExport, 0, len(identities))
+	for _, identity := range identities {
+		exports = append(exports, identityExport{
+			ID:          identity.ID.Hex(),
+			Provider:    identity.Provider,
+			ProviderID:  identity.ProviderID,
+			Email:       identity.Email,
+			LastLoginAt: identity.LastLoginAt,
+			CreatedAt:   identity.CreatedAt,
+			UpdatedAt:   identity.UpdatedAt,
+		})
+	}
+
+	return exports, nil
+}
+
+type sessionExportProvider struct {
+	sessionRepo domain.SessionRepository
+}
+
+// NewSessionExportProvider creates an export provider for the user's session history.
+func NewSessionExportProvider(sessionRepo domain.SessionRepository) export.Provider {
+	return &sessionExportProvider{sessionRepo: sessionRepo}
+}
+
+func (p *sessionExportProvider) Name() string {
+	return "sessions"
+}
+
+func (p *sessionExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	sessions, err := p.sessionRepo.ListSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]sessionExport, 0, len(sessions))
+	for _, session := range sessions {
+		exports = append(exports, sessionExport{
+			ID:                    session.ID.Hex(),
+			IPAddress:             session.IPAddress,
+			UserAgent:             session.UserAgent,
+			AccessTokenExpiresAt:  session.AccessTokenExpiresAt,
+			RefreshTokenExpiresAt: session.RefreshTokenExpiresAt,
+			CreatedAt:             session.CreatedAt,
+			UpdatedAt:             session.UpdatedAt,
+		})
+	}
+
+	return exports, nil
+}
+
+// authEventExportPageSize is how many audit log events are read per page while exporting.
+const authEventExportPageSize = 100
+
+type authEventExportProvider struct {
+	authEventRepo domain.AuthEventRepository
+}
+
+// NewAuthEventExportProvider creates an export provider for the user's security audit log.
+func NewAuthEventExportProvider(authEventRepo domain.AuthEventRepository) export.Provider {
+	return &authEventExportProvider{authEventRepo: authEventRepo}
+}
+
+func (p *authEventExportProvider) Name() string {
+	return "auth_events"
+}
+
+func (p *authEventExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	exports := make([]authEventExport, 0)
+	params := domain.FilterAuthEventParams{Limit: authEventExportPageSize}
+	for {
+		events, next, err := p.authEventRepo.ListAuthEventsByUserID(ctx, userID, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			exports = append(exports, authEventExport{
+				ID:        event.ID.Hex(),
+				Type:      string(event.Type),
+				Email:     event.Email,
+				SessionID: event.SessionID,
+				IPAddress: event.IPAddress,
+				UserAgent: event.UserAgent,
+				Reason:    event.Reason,
+				CreatedAt: event.CreatedAt,
+			})
+		}
+
+		if next == nil {
+			return exports, nil
+		}
+		params.After = next
+	}
+}
+
+type knownDeviceExportProvider struct {
+	knownDeviceRepo domain.KnownDeviceRepository
+}
+
+// NewKnownDeviceExportProvider creates an export provider for the devices the user has signed in from.
+func NewKnownDeviceExportProvider(knownDeviceRepo domain.KnownDeviceRepository) export.Provider {
+	return &knownDeviceExportProvider{knownDeviceRepo: knownDeviceRepo}
+}
+
+func (p *knownDeviceExportProvider) Name() string {
+	return "known_devices"
+}
+
+func (p *knownDeviceExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	devices, err := p.knownDeviceRepo.GetKnownDevicesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]knownDeviceExport, 0, len(devices))
+	for _, device := range devices {
+		exports = append(exports, knownDeviceExport{
+			ID:          device.ID.Hex(),
+			Fingerprint: device.Fingerprint,
+			IPAddress:   device.IPAddress,
+			UserAgent:   device.UserAgent,
+			FirstSeenAt: device.FirstSeenAt,
+			LastSeenAt:  device.LastSeenAt,
+		})
+	}
+
+	return exports, nil
+}
+
+type loginAlertExportProvider struct {
+	loginAlertRepo domain.LoginAlertRepository
+}
+
+// NewLoginAlertExportProvider creates an export provider for the new device login alerts sent to the user.
+func NewLoginAlertExportProvider(loginAlertRepo domain.LoginAlertRepository) export.Provider {
+	return &loginAlertExportProvider{loginAlertRepo: loginAlertRepo}
+}
+
+func (p *loginAlertExportProvider) Name() string {
+	return "login_alerts"
+}
+
+func (p *loginAlertExportProvider) Export(ctx context.Context, userID string) (any, error) {
+	alerts, err := p.loginAlertRepo.ListLoginAlertsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]loginAlertExport, 0, len(alerts))
+	for _, alert := range alerts {
+		exports = append(exports, loginAlertExport{
+			ID:        alert.ID.Hex(),
+			SessionID: alert.SessionID,
+			ExpiresAt: alert.ExpiresAt,
+			UsedAt:    alert.UsedAt,
+			CreatedAt: alert.CreatedAt,
+		})
+	}
+
+	return exports, nil
+}
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/repository/memory"
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/usecase"
+	"github.com/vasapolrittideah/moneylog-api/shared/export"
+)
+
+func TestGetExportJob(t *testing.T) {
+	ctx := context.Background()
+	jobs := memory.NewExportJobRepository()
+	exports := usecase.NewExportUsecase(jobs, memory.NewUserRepository(), export.NewRegistry())
+
+	job, err := jobs.CreateExportJob(ctx, &domain.ExportJob{UserID: "u1", Status: domain.ExportStatusPending})
+	if err != nil {
+		t.Fatalf("CreateExportJob: %v", err)
+	}
+
+	if got, err := exports.GetExportJob(ctx, "u1", job.ID.Hex()); err != nil || got.ID != job.ID {
+		t.Fatalf("GetExportJob returned %v, %v, want the job", got, err)
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		jobID  string
+	}{
+		{name: "job of another user", userID: "u2", jobID: job.ID.Hex()},
+		{name: "unknown job", userID: "u1", jobID: "0123456789abcdef01234567"},
+		{name: "malformed ID", userID: "u1", jobID: "not-an-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := exports.GetExportJob(ctx, tt.userID, tt.jobID); !errors.Is(err, usecase.ErrExportJobNotFound) {
+				t.Errorf("GetExportJob returned %v, want %v", err, usecase.ErrExportJobNotFound)
+			}
+			_, err := exports.DownloadExport(ctx, tt.userID, tt.jobID)
+			if !errors.Is(err, usecase.ErrExportJobNotFound) {
+				t.Errorf("DownloadExport returned %v, want %v", err, usecase.ErrExportJobNotFound)
+			}
+		})
+	}
+}
+
+func TestExportProviders(t *testing.T) {
+	ctx := context.Background()
+
+	users := memory.NewUserRepository()
+	user, err := users.CreateUser(ctx, &domain.User{
+		FullName:              "Jane Doe",
+		Email:                 "jane@example.com",
+		PasswordResetRequired: true,
+		DisplayName:           "Jane",
+		Locale:                "th-TH",
+		Timezone:              "Asia/Bangkok",
+		DefaultCurrency:       "THB",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	alerts := memory.NewLoginAlertRepository()
+	if _, err := alerts.CreateLoginAlert(ctx, &domain.LoginAlert{
+		UserID:    user.ID.Hex(),
+		SessionID: "s1",
+		TokenHash: "secret-token-hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("CreateLoginAlert: %v", err)
+	}
+
+	tests := []struct {
+		provider export.Provider
+		want     []string
+		omit     []string
+	}{
+		{
+			provider: usecase.NewUserExportProvider(users),
+			want: []string{
+				`"display_name":"Jane"`,
+				`"locale":"th-TH"`,
+				`"timezone":"Asia/Bangkok"`,
+				`"default_currency":"THB"`,
+				`"password_reset_required":true`,
+			},
+			omit: []string{"password_hash"},
+		},
+		{
+			provider: usecase.NewLoginAlertExportProvider(alerts),
+			want:     []string{`"session_id":"s1"`},
+			omit:     []string{"secret-token-hash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider.Name(), func(t *testing.T) {
+			data, err := tt.provider.Export(ctx, user.ID.Hex())
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			body, err := json.Marshal(data)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("export %s does not contain %s", body, want)
+				}
+			}
+			for _, omit := range tt.omit {
+				if strings.Contains(string(body), omit) {
+					t.Errorf("export %s contains %s", body, omit)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/mail"
+	"github.com/vasapolrittideah/moneylog-api/shared/security"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrPasswordResetRequired = errors.New("password reset required")
+	ErrInvalidLoginAlert     = errors.New("invalid or expired login alert")
+)
+
+const (
+	// loginAlertTTL is how long the "wasn't you?" link in a new device email stays valid.
+	loginAlertTTL = 72 * time.Hour
+	// newDeviceCheckTimeout bounds the background known device check and notification.
+	newDeviceCheckTimeout = 30 * time.Second
+)
+
+func (u *authUsecase) RevokeSuspiciousLogin(ctx context.Context, token string) error {
+	alert, err := u.loginAlertRepo.GetLoginAlertByTokenHash(ctx, security.HashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidLoginAlert
+		}
+
+		return err
+	}
+
+	if alert.UsedAt != nil || time.Now().After(alert.ExpiresAt) {
+		return ErrInvalidLoginAlert
+	}
+
+	if err := u.sessionRepo.RevokeSession(ctx, alert.SessionID); err != nil {
+		return err
+	}
+
+	resetRequired := true
+	if _, err := u.userRepo.UpdateUser(ctx, alert.UserID, domain.UpdateUserParams{
+		PasswordResetRequired: &resetRequired,
+	}); err != nil {
+		return err
+	}
+
+	if err := u.loginAlertRepo.MarkLoginAlertUsed(ctx, alert.ID.Hex()); err != nil {
+		return err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    alert.UserID,
+		Type:      domain.AuthEventSuspiciousLoginReported,
+		SessionID: alert.SessionID,
+	})
+
+	return nil
+}
+
+// checkKnownDevice remembers the device and IP address of a new session and, when the user has
+// signed in before but never from this device or IP address, emails them a "wasn't you?" link.
+// It runs in the background so that the login response is never held up by the notification.
+func (u *authUsecase) checkKnownDevice(ctx context.Context, user domain.User, session domain.Session) {
+	ctx, cancel := context.WithTimeout(ctx, newDeviceCheckTimeout)
+	defer cancel()
+
+	log := logger.FromContext(ctx).With().Str("userID", user.ID.Hex()).Str("sessionID", session.ID.Hex()).Logger()
+
+	ipAddress := stringValue(session.IPAddress)
+	userAgent := stringValue(session.UserAgent)
+	fingerprint := deviceFingerprint(userAgent)
+
+	devices, err := u.knownDeviceRepo.GetKnownDevicesByUserID(ctx, user.ID.Hex())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get known devices")
+		return
+	}
+
+	var deviceSeen, ipSeen bool
+	for _, device := range devices {
+		deviceSeen = deviceSeen || device.Fingerprint == fingerprint
+		ipSeen = ipSeen || device.IPAddress == ipAddress
+	}
+
+	if err := u.knownDeviceRepo.UpsertKnownDevice(ctx, &domain.KnownDevice{
+		UserID:      user.ID.Hex(),
+		Fingerprint: fingerprint,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to remember known device")
+		return
+	}
+
+	// The first device a user signs in from becomes the baseline and is not reported.
+	if len(devices) == 0 || (deviceSeen && ipSeen) {
+		return
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    user.ID.Hex(),
+		Type:      domain.AuthEventNewDeviceLogin,
+		Email:     user.Email,
+		SessionID: session.ID.Hex(),
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+	})
+
+	if err := u.sendLoginAlert(ctx, user, session); err != nil {
+		log.Error().Err(err).Msg("Failed to send new device login alert")
+	}
+}
+
+func (u *authUsecase) sendLoginAlert(ctx context.Context, user domain.User, session domain.Session) error {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.loginAlertRepo.CreateLoginAlert(ctx, &domain.LoginAlert{
+		UserID:    user.ID.Hex(),
+		SessionID: session.ID.Hex(),
+		TokenHash: security.HashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(loginAlertTTL),
+	}); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(u.authServiceCfg.Notification.LoginAlertURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\n"+
+			"We noticed a new sign-in to your Moneylog account.\n\n"+
+			"Time: %s\nIP address: %s\nDevice: %s\n\n"+
+			"If this was you, you can ignore this email.\n"+
+			"If this wasn't you, sign this device out here:\n%s\n"+
+			"You will then need to reset your password before signing in again.\n",
+		user.FullName,
+		session.CreatedAt.UTC().Format(time.RFC1123),
+		stringValue(session.IPAddress),
+		stringValue(session.UserAgent),
+		link.String(),
+	)
+
+	return u.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "New sign-in to your Moneylog account",
+		Body:    body,
+	})
+}
+
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/mail"
+	"github.com/vasapolrittideah/moneylog-api/shared/security"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var ErrInvalidPasswordReset = errors.New("invalid or expired password reset")
+
+const (
+	// passwordResetTTL is how long the link in a password reset email stays valid.
+	passwordResetTTL = time.Hour
+	// passwordResetEmailTimeout bounds the background sending of a password reset email.
+	passwordResetEmailTimeout = 30 * time.Second
+)
+
+// RequestPasswordReset emails the user a link to reset their password. It succeeds whether or not
+// an account has the email address, and sends the email in the background, so that the response
+// tells nothing about which addresses have accounts.
+func (u *authUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetEmailTimeout)
+		defer cancel()
+
+		if err := u.sendPasswordReset(ctx, *user); err != nil {
+			logger.FromContext(ctx).Error().Err(err).Str("userID", user.ID.Hex()).Msg("Failed to send password reset")
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword sets a new password with the token of a password reset email. It lifts the reset
+// required by a reported suspicious login and signs the user out of every session.
+func (u *authUsecase) ResetPassword(ctx context.Context, params domain.ResetPasswordParams) error {
+	reset, err := u.passwordResetRepo.GetPasswordResetByTokenHash(ctx, security.HashOpaqueToken(params.Token))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidPasswordReset
+		}
+
+		return err
+	}
+
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidPasswordReset
+	}
+
+	passwordHash, err := security.HashPassword(params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// Claiming the reset before the password changes lets a token be redeemed only once, even by
+	// concurrent requests.
+	if err := u.passwordResetRepo.MarkPasswordResetUsed(ctx, reset.ID.Hex()); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidPasswordReset
+		}
+
+		return err
+	}
+
+	resetRequired := false
+	if _, err := u.userRepo.UpdateUser(ctx, reset.UserID, domain.UpdateUserParams{
+		PasswordHash:          &passwordHash,
+		PasswordResetRequired: &resetRequired,
+	}); err != nil {
+		return err
+	}
+
+	if err := u.revokeSessions(ctx, reset.UserID, ""); err != nil {
+		return err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID: reset.UserID,
+		Type:   domain.AuthEventPasswordReset,
+	})
+
+	return nil
+}
+
+// ChangePassword sets a new password for a signed in user who knows their current one. The user
+// stays signed in to the session the change was made from and is signed out of every other one.
+// A reset required by a reported suspicious login is not lifted, since whoever signed in may know
+// the current password; that takes a password reset email.
+func (u *authUsecase) ChangePassword(ctx context.Context, params domain.ChangePasswordParams) error {
+	user, err := u.userRepo.GetUser(ctx, params.UserID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	if ok, err := security.VerifyPassword(params.CurrentPassword, user.PasswordHash); err != nil {
+		return err
+	} else if !ok {
+		return ErrInvalidCredentials
+	}
+
+	passwordHash, err := security.HashPassword(params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.userRepo.UpdateUser(ctx, params.UserID, domain.UpdateUserParams{
+		PasswordHash: &passwordHash,
+	}); err != nil {
+		return err
+	}
+
+	if err := u.revokeSessions(ctx, params.UserID, params.SessionID); err != nil {
+		return err
+	}
+
+	u.recordEvent(ctx, &domain.AuthEvent{
+		UserID:    params.UserID,
+		Type:      domain.AuthEventPasswordChanged,
+		SessionID: params.SessionID,
+		IPAddress: optionalString(params.IPAddress),
+		UserAgent: optionalString(params.UserAgent),
+	})
+
+	return nil
+}
+
+func (u *authUsecase) sendPasswordReset(ctx context.Context, user domain.User) error {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.passwordResetRepo.CreatePasswordReset(ctx, &domain.PasswordReset{
+		UserID:    user.ID.Hex(),
+		TokenHash: security.HashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(u.authServiceCfg.Notification.PasswordResetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\n"+
+			"We received a request to reset the password of your Moneylog account.\n\n"+
+			"Choose a new password here within the next hour:\n%s\n\n"+
+			"If you didn't ask to reset your password, you can ignore this email.\n",
+		user.FullName,
+		link.String(),
+	)
+
+	return u.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Moneylog password",
+		Body:    body,
+	})
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+)
+
+// RecordActiveSessions counts the active sessions every interval and publishes the count as a
+// metric until ctx is cancelled. Counting from the repository keeps the gauge correct across
+// restarts and replicas, which incrementing it on login and logout would not.
+func RecordActiveSessions(ctx context.Context, sessionRepo domain.SessionRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := sessionRepo.CountActiveSessions(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Error().Err(err).Msg("Failed to count active sessions")
+		} else {
+			metrics.SetActiveSessions(count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
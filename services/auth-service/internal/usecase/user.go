@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/services/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+type userUsecase struct {
+	userRepo domain.UserRepository
+}
+
+func NewUserUsecase(userRepo domain.UserRepository) domain.UserUsecase {
+	return &userUsecase{
+		userRepo: userRepo,
+	}
+}
+
+func (u *userUsecase) GetMe(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := u.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userUsecase) UpdateProfile(ctx context.Context, params domain.UpdateProfileParams) (*domain.User, error) {
+	if params.Timezone != nil && *params.Timezone != "" {
+		if _, err := time.LoadLocation(*params.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	updateParams := domain.UpdateUserParams{
+		FullName:        params.FullName,
+		DisplayName:     params.DisplayName,
+		AvatarURL:       params.AvatarURL,
+		Locale:          params.Locale,
+		Timezone:        params.Timezone,
+		DefaultCurrency: params.DefaultCurrency,
+	}
+	if updateParams == (domain.UpdateUserParams{}) {
+		return u.GetMe(ctx, params.UserID)
+	}
+
+	user, err := u.userRepo.UpdateUser(ctx, params.UserID, updateParams)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
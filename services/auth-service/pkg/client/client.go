@@ -1,11 +1,23 @@
 package authclient
 
 import (
+	"context"
+
 	"github.com/vasapolrittideah/moneylog-api/shared/discovery"
+	"github.com/vasapolrittideah/moneylog-api/shared/healthcheck"
 	authpbv1 "github.com/vasapolrittideah/moneylog-api/shared/protos/auth/v1"
+	"github.com/vasapolrittideah/moneylog-api/shared/resilience"
 	"google.golang.org/grpc"
 )
 
+// idempotentMethods are the methods that are safe to retry.
+var idempotentMethods = []string{
+	authpbv1.AuthService_GetMe_FullMethodName,
+	authpbv1.AuthService_GetExportJob_FullMethodName,
+	authpbv1.AuthService_DownloadExport_FullMethodName,
+	authpbv1.AuthService_ListSecurityEvents_FullMethodName,
+}
+
 type AuthServiceClient struct {
 	Client authpbv1.AuthServiceClient
 	conn   *grpc.ClientConn
@@ -14,8 +26,12 @@ type AuthServiceClient struct {
 func NewAuthServiceClient(
 	consulRegistry *discovery.ConsulRegistry,
 	authServiceName string,
+	resilienceCfg *resilience.ClientConfig,
 ) (*AuthServiceClient, error) {
-	conn, err := consulRegistry.Connect(authServiceName)
+	conn, err := consulRegistry.Connect(
+		authServiceName,
+		resilience.DialOptions(authServiceName, resilienceCfg, idempotentMethods...)...,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +44,11 @@ func NewAuthServiceClient(
 	}, nil
 }
 
+// Check checks that the auth service reports SERVING through the gRPC health checking protocol.
+func (c *AuthServiceClient) Check(ctx context.Context) error {
+	return healthcheck.GRPCCheck(c.conn, "")(ctx)
+}
+
 func (c *AuthServiceClient) Close() error {
 	if c.conn != nil {
 		if err := c.conn.Close(); err != nil {
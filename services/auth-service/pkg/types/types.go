@@ -12,4 +12,5 @@ type JWTClaims struct {
 
 	UserID    string `json:"user_id"`
 	SessionID string `json:"session_id"`
+	Locale    string `json:"locale,omitempty"`
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -43,3 +44,19 @@ func (a *JWTAuthenticator) ValidateToken(token string, secret string) (*jwt.Toke
 		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
 	)
 }
+
+// ExpiresAt returns the expiry of the token without verifying its signature, or the zero time when
+// it has none. It is meant for tokens that were just issued by the auth service.
+func ExpiresAt(token string) time.Time {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return time.Time{}
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}
+	}
+
+	return exp.Time
+}
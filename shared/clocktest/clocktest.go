@@ -0,0 +1,35 @@
+// Package clocktest provides a manually advanced clock for tests of code that reads the time
+// through a now function.
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a clock that only moves when it is advanced. It is safe for concurrent use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// New creates a clock set to a fixed time.
+func New() *Clock {
+	return &Clock{now: time.Unix(1_700_000_000, 0)}
+}
+
+// Now returns the current time of the clock; pass it as the now function of the code under test.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
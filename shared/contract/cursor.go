@@ -0,0 +1,80 @@
+package contract
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed, were not issued with the codec's
+// secret, or belong to a list sorted by another field.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position after the last item of a page in a list sorted by a field and then by
+// _id. Clients receive it as an opaque, signed token and send it back to fetch the next page.
+type Cursor struct {
+	// Sort is the field the list is sorted by.
+	Sort string `bson:"s"`
+	// Key is the value of the sort field of the last item. It is encoded as BSON, so that dates and
+	// numbers keep their type and compare like the stored values.
+	Key any `bson:"k"`
+	// ID is the hex encoded _id of the last item.
+	ID string `bson:"id"`
+}
+
+// CursorCodec encodes cursors as tokens signed with HMAC-SHA256, so that clients cannot forge
+// positions, e.g. to skip the filters of a query.
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec creates a cursor codec that signs tokens with the secret.
+func NewCursorCodec(secret string) *CursorCodec {
+	return &CursorCodec{secret: []byte(secret)}
+}
+
+// Encode returns the token of the cursor.
+func (c *CursorCodec) Encode(cursor Cursor) (string, error) {
+	payload, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode returns the cursor of a token issued by Encode.
+func (c *CursorCodec) Decode(token string) (*Cursor, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := bson.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
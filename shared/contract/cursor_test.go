@@ -0,0 +1,60 @@
+package contract_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := contract.NewCursorCodec("secret")
+	createdAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	id := bson.NewObjectID().Hex()
+
+	t.Run("round-trips the cursor and keeps the key's type", func(t *testing.T) {
+		token, err := codec.Encode(contract.Cursor{Sort: "created_at", Key: createdAt, ID: id})
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+
+		cursor, err := codec.Decode(token)
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if cursor.Sort != "created_at" || cursor.ID != id {
+			t.Fatalf("cursor is %+v, want sort created_at and ID %s", cursor, id)
+		}
+		if key, ok := cursor.Key.(bson.DateTime); !ok || !key.Time().Equal(createdAt) {
+			t.Fatalf("key is %#v, want the date %v", cursor.Key, createdAt)
+		}
+	})
+
+	t.Run("rejects tampered and foreign tokens", func(t *testing.T) {
+		token, err := codec.Encode(contract.Cursor{Sort: "email", Key: "alice@example.com", ID: id})
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		payload, signature, _ := strings.Cut(token, ".")
+		forged, err := contract.NewCursorCodec("other").Encode(contract.Cursor{Sort: "email", Key: "bob", ID: id})
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		forgedPayload, _, _ := strings.Cut(forged, ".")
+
+		for _, token := range []string{
+			"",
+			payload,
+			forgedPayload + "." + signature,
+			forged,
+			"!!!." + signature,
+		} {
+			if _, err := codec.Decode(token); !errors.Is(err, contract.ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error is %v, want ErrInvalidCursor", token, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain is the google.rpc.ErrorInfo domain of errors raised by moneylog services. Only the
+// messages of errors from this domain are considered safe to show to API clients.
+const ErrorDomain = "api.moneylog"
+
+const internalErrorMessage = "internal server error"
+
+// NewGRPCError creates a gRPC status error carrying an ErrorInfo with the reason, followed by any
+// additional details such as BadRequest or RetryInfo. The reason becomes APIError.Code and the
+// message is returned to the client as is, so it must not contain internal error text.
+func NewGRPCError(code codes.Code, reason, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	}}, details...)
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// NewBadRequest creates a BadRequest detail describing the invalid fields of a request.
+func NewBadRequest(violations ...*errdetails.BadRequest_FieldViolation) *errdetails.BadRequest {
+	return &errdetails.BadRequest{FieldViolations: violations}
+}
+
+// NewFieldViolation creates a BadRequest field violation.
+func NewFieldViolation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+// NewRetryInfo creates a RetryInfo detail telling the client how long to wait before retrying.
+func NewRetryInfo(delay time.Duration) *errdetails.RetryInfo {
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+}
+
+// GRPCErrorResponse is a gRPC error translated for an HTTP client.
+type GRPCErrorResponse struct {
+	Status     int
+	Response   APIResponse
+	RetryAfter time.Duration
+}
+
+// RetryAfterSeconds returns the Retry-After header value in whole seconds, rounded up, or zero
+// when the server did not ask the client to retry later.
+func (r GRPCErrorResponse) RetryAfterSeconds() int {
+	return int(math.Ceil(r.RetryAfter.Seconds()))
+}
+
+// NewGRPCErrorResponse translates a gRPC error into an HTTP status and error response. The
+// ErrorInfo reason becomes the error code, BadRequest field violations become the error details and
+// RetryInfo becomes the retry delay. Errors that did not come from a moneylog service, and internal
+// errors from any source, are reduced to a generic message so that no internal detail leaks out.
+func NewGRPCErrorResponse(err error) GRPCErrorResponse {
+	st := status.Convert(err)
+	httpStatus := HTTPStatusFromGRPCCode(st.Code())
+	resp := GRPCErrorResponse{
+		Status:   httpStatus,
+		Response: NewErrorResponse(ErrorCodeFromGRPCCode(st.Code()), http.StatusText(httpStatus)),
+	}
+
+	if isInternalCode(st.Code()) {
+		resp.Response.Error.Message = internalErrorMessage
+		return resp
+	}
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() == ErrorDomain {
+				resp.Response.Error.Code = detail.GetReason()
+				resp.Response.Error.Message = st.Message()
+			}
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				resp.Response.Error.Details = append(resp.Response.Error.Details, APIValidationError{
+					Field:   violation.GetField(),
+					Message: violation.GetDescription(),
+				})
+			}
+		case *errdetails.RetryInfo:
+			resp.RetryAfter = detail.GetRetryDelay().AsDuration()
+		}
+	}
+
+	return resp
+}
+
+func isInternalCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package contract_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewGRPCErrorResponse(t *testing.T) {
+	t.Run("uses the reason and field violations of service errors", func(t *testing.T) {
+		err := contract.NewGRPCError(
+			codes.InvalidArgument,
+			contract.ErrorCodeValidation,
+			"Validation failed",
+			contract.NewBadRequest(contract.NewFieldViolation("timezone", "invalid timezone")),
+		)
+
+		resp := contract.NewGRPCErrorResponse(err)
+		if resp.Status != http.StatusBadRequest {
+			t.Fatalf("status is %d, want %d", resp.Status, http.StatusBadRequest)
+		}
+		if resp.Response.Error.Code != contract.ErrorCodeValidation {
+			t.Fatalf("code is %q, want %q", resp.Response.Error.Code, contract.ErrorCodeValidation)
+		}
+		if resp.Response.Error.Message != "Validation failed" {
+			t.Fatalf("message is %q, want the service message", resp.Response.Error.Message)
+		}
+		details := resp.Response.Error.Details
+		if len(details) != 1 || details[0].Field != "timezone" || details[0].Message != "invalid timezone" {
+			t.Fatalf("details are %+v, want the timezone violation", details)
+		}
+	})
+
+	t.Run("rounds the retry delay up to whole seconds", func(t *testing.T) {
+		err := contract.NewGRPCError(
+			codes.FailedPrecondition,
+			contract.ErrorCodeExportNotReady,
+			"export is not ready",
+			contract.NewRetryInfo(1500*time.Millisecond),
+		)
+
+		resp := contract.NewGRPCErrorResponse(err)
+		if got := resp.RetryAfterSeconds(); got != 2 {
+			t.Fatalf("RetryAfterSeconds is %d, want 2", got)
+		}
+		if resp.Response.Error.Code != contract.ErrorCodeExportNotReady {
+			t.Fatalf("code is %q, want %q", resp.Response.Error.Code, contract.ErrorCodeExportNotReady)
+		}
+	})
+
+	t.Run("hides the message of internal errors", func(t *testing.T) {
+		err := contract.NewGRPCError(codes.Internal, contract.ErrorCodeInternal, "mongo: connection refused")
+
+		resp := contract.NewGRPCErrorResponse(err)
+		if resp.Status != http.StatusInternalServerError {
+			t.Fatalf("status is %d, want %d", resp.Status, http.StatusInternalServerError)
+		}
+		if resp.Response.Error.Message == "mongo: connection refused" {
+			t.Fatal("internal error message leaked to the client")
+		}
+	})
+
+	t.Run("hides the message of errors without service details", func(t *testing.T) {
+		err := status.Error(codes.Unavailable, "dial tcp 10.0.0.12:9001: connect: connection refused")
+
+		resp := contract.NewGRPCErrorResponse(err)
+		if resp.Status != http.StatusServiceUnavailable {
+			t.Fatalf("status is %d, want %d", resp.Status, http.StatusServiceUnavailable)
+		}
+		if resp.Response.Error.Code != contract.ErrorCodeUnavailable {
+			t.Fatalf("code is %q, want %q", resp.Response.Error.Code, contract.ErrorCodeUnavailable)
+		}
+		if resp.Response.Error.Message != http.StatusText(http.StatusServiceUnavailable) {
+			t.Fatalf("message is %q, want the generic status text", resp.Response.Error.Message)
+		}
+	})
+
+	t.Run("treats non-status errors as internal", func(t *testing.T) {
+		resp := contract.NewGRPCErrorResponse(errors.New("boom"))
+		if resp.Status != http.StatusInternalServerError || resp.Response.Error.Message == "boom" {
+			t.Fatalf("got status %d and message %q, want a sanitized internal error",
+				resp.Status, resp.Response.Error.Message)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package contract
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -11,9 +14,18 @@ import (
 type APIResponse struct {
 	Data      any       `json:"data,omitempty"`
 	Error     *APIError `json:"error,omitempty"`
+	PageInfo  *PageInfo `json:"page_info,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// PageInfo describes where a page of a cursor-paginated list ends. NextCursor is sent back as the
+// cursor query parameter to fetch the following page.
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
 // APIError is the error structure for the API.
 type APIError struct {
 	Code    string               `json:"code"`
@@ -37,6 +49,23 @@ const (
 	ErrorCodeBadRequest   = "BAD_REQUEST"
 	ErrorCodeConflict     = "CONFLICT"
 	ErrorCodeRateLimit    = "RATE_LIMIT_EXCEEDED"
+
+	ErrorCodeFailedPrecondition = "FAILED_PRECONDITION"
+	ErrorCodeUnavailable        = "SERVICE_UNAVAILABLE"
+	ErrorCodeTimeout            = "TIMEOUT"
+	ErrorCodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	ErrorCodeNotImplemented     = "NOT_IMPLEMENTED"
+
+	ErrorCodeInvalidCredentials    = "INVALID_CREDENTIALS"
+	ErrorCodeInvalidRefreshToken   = "INVALID_REFRESH_TOKEN"
+	ErrorCodeInvalidCursor         = "INVALID_CURSOR"
+	ErrorCodeUserNotFound          = "USER_NOT_FOUND"
+	ErrorCodeUserAlreadyExists     = "USER_ALREADY_EXISTS"
+	ErrorCodePasswordResetRequired = "PASSWORD_RESET_REQUIRED"
+	ErrorCodeInvalidLoginAlert     = "INVALID_LOGIN_ALERT"
+	ErrorCodeInvalidPasswordReset  = "INVALID_PASSWORD_RESET"
+	ErrorCodeExportJobNotFound     = "EXPORT_JOB_NOT_FOUND"
+	ErrorCodeExportNotReady        = "EXPORT_NOT_READY"
 )
 
 func NewSuccessResponse(data any) APIResponse {
@@ -46,6 +75,35 @@ func NewSuccessResponse(data any) APIResponse {
 	}
 }
 
+func NewPageResponse(data any, pageInfo PageInfo) APIResponse {
+	return APIResponse{
+		Data:      data,
+		PageInfo:  &pageInfo,
+		Timestamp: time.Now(),
+	}
+}
+
+// ETag returns a strong entity tag of the response's content. The request ID and timestamp differ
+// on every response and are left out, so that the tag only changes when the content does.
+func (r APIResponse) ETag() (string, error) {
+	r.RequestID = ""
+	r.Timestamp = time.Time{}
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+
+	return NewETag(body), nil
+}
+
+// NewETag returns a strong entity tag identifying the content, which may be a serialized response
+// or a version of the resource it represents.
+func NewETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
 func NewErrorResponse(code, message string) APIResponse {
 	return APIResponse{
 		Error: &APIError{
@@ -78,7 +136,7 @@ func ErrorCodeFromGRPCCode(code codes.Code) string {
 	case codes.InvalidArgument:
 		return ErrorCodeBadRequest
 	case codes.DeadlineExceeded:
-		return ErrorCodeInternal
+		return ErrorCodeTimeout
 	case codes.NotFound:
 		return ErrorCodeNotFound
 	case codes.AlreadyExists:
@@ -87,6 +145,18 @@ func ErrorCodeFromGRPCCode(code codes.Code) string {
 		return ErrorCodeForbidden
 	case codes.ResourceExhausted:
 		return ErrorCodeRateLimit
+	case codes.FailedPrecondition:
+		return ErrorCodeFailedPrecondition
+	case codes.Aborted:
+		return ErrorCodeConflict
+	case codes.OutOfRange:
+		return ErrorCodeBadRequest
+	case codes.Unimplemented:
+		return ErrorCodeNotImplemented
+	case codes.Unavailable:
+		return ErrorCodeUnavailable
+	case codes.Unauthenticated:
+		return ErrorCodeUnauthorized
 	default:
 		return ErrorCodeInternal
 	}
@@ -0,0 +1,60 @@
+package contract_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAPIResponseETag(t *testing.T) {
+	etag := func(resp contract.APIResponse) string {
+		t.Helper()
+
+		tag, err := resp.ETag()
+		if err != nil {
+			t.Fatalf("ETag: %v", err)
+		}
+		return tag
+	}
+
+	first := contract.NewPageResponse([]string{"a", "b"}, contract.PageInfo{NextCursor: "c", HasMore: true})
+	first.RequestID = "req-1"
+	second := contract.NewPageResponse([]string{"a", "b"}, contract.PageInfo{NextCursor: "c", HasMore: true})
+	second.RequestID = "req-2"
+	second.Timestamp = first.Timestamp.Add(time.Minute)
+
+	tag := etag(first)
+	if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		t.Fatalf("ETag is %s, want a quoted strong tag", tag)
+	}
+	if etag(second) != tag {
+		t.Fatalf("ETags differ by request ID and timestamp: %s and %s", tag, etag(second))
+	}
+
+	for _, other := range []contract.APIResponse{
+		contract.NewPageResponse([]string{"a", "b"}, contract.PageInfo{HasMore: false}),
+		contract.NewSuccessResponse([]string{"a"}),
+	} {
+		if etag(other) == tag {
+			t.Fatalf("ETag of %+v is %s too", other, tag)
+		}
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	status := contract.HTTPStatusFromGRPCCode(codes.Unimplemented)
+	errorCode := contract.ErrorCodeFromGRPCCode(codes.Unimplemented)
+	if status != http.StatusNotImplemented || errorCode != contract.ErrorCodeNotImplemented {
+		t.Errorf(
+			"Unimplemented maps to %d %s, want %d %s",
+			status,
+			errorCode,
+			http.StatusNotImplemented,
+			contract.ErrorCodeNotImplemented,
+		)
+	}
+}
@@ -68,7 +68,12 @@ func (m *MongoDB) Connect(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, DefaultConnectionTimeout)
 	defer cancel()
 
-	client, err := mongo.Connect(options.Client().ApplyURI(m.config.URI))
+	client, err := mongo.Connect(
+		options.Client().
+			ApplyURI(m.config.URI).
+			SetMonitor(newCommandMonitor()).
+			SetPoolMonitor(newPoolMonitor()),
+	)
 	if err != nil {
 		return err
 	}
@@ -94,6 +99,15 @@ func (m *MongoDB) Disconnect(ctx context.Context) error {
 	return m.client.Disconnect(ctx)
 }
 
+// Ping checks that the primary is reachable.
+func (m *MongoDB) Ping(ctx context.Context) error {
+	if m.client == nil {
+		return errors.New("mongo client is not connected")
+	}
+
+	return m.client.Ping(ctx, readpref.Primary())
+}
+
 // GetDatabase returns the MongoDB database.
 func (m *MongoDB) GetDatabase() *mongo.Database {
 	return m.database
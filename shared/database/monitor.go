@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/vasapolrittideah/moneylog-api/shared/database"
+
+// commandMonitor records a client span and a latency observation for every command sent to MongoDB.
+// Spans are started from the operation's context, so they become children of the span of the
+// request that issued them.
+type commandMonitor struct {
+	tracer trace.Tracer
+	spans  sync.Map
+}
+
+// newCommandMonitor creates a command monitor that traces MongoDB commands with the global tracer
+// provider and records their latency.
+func newCommandMonitor() *event.CommandMonitor {
+	t := &commandMonitor{tracer: otel.Tracer(tracerName)}
+
+	return &event.CommandMonitor{
+		Started:   t.started,
+		Succeeded: t.succeeded,
+		Failed:    t.failed,
+	}
+}
+
+func (t *commandMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	attrs := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBNamespace(evt.DatabaseName),
+			semconv.DBOperationName(evt.CommandName),
+		),
+	}
+
+	name := evt.CommandName
+	if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		name = evt.CommandName + " " + collection
+		attrs = append(attrs, trace.WithAttributes(semconv.DBCollectionName(collection)))
+	}
+
+	_, span := t.tracer.Start(ctx, name, attrs...)
+	t.spans.Store(evt.RequestID, span)
+}
+
+func (t *commandMonitor) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	metrics.ObserveMongoCommand(evt.CommandName, false, evt.Duration)
+
+	if span, ok := t.spans.LoadAndDelete(evt.RequestID); ok {
+		span.(trace.Span).End()
+	}
+}
+
+func (t *commandMonitor) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	metrics.ObserveMongoCommand(evt.CommandName, true, evt.Duration)
+
+	if value, ok := t.spans.LoadAndDelete(evt.RequestID); ok {
+		span := value.(trace.Span)
+		span.RecordError(evt.Failure)
+		span.SetStatus(codes.Error, evt.Failure.Error())
+		span.End()
+	}
+}
+
+// newPoolMonitor creates a pool monitor that records the size and usage of the connection pool.
+func newPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				metrics.MongoConnectionOpened(evt.Address)
+			case event.ConnectionClosed:
+				metrics.MongoConnectionClosed(evt.Address)
+			case event.ConnectionCheckedOut:
+				metrics.MongoConnectionCheckedOut(evt.Address)
+			case event.ConnectionCheckedIn:
+				metrics.MongoConnectionCheckedIn(evt.Address)
+			case event.ConnectionCheckOutFailed:
+				metrics.MongoConnectionCheckOutFailed(evt.Address, evt.Reason)
+			}
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// PageQuery describes a page of a query sorted by SortField and then by _id, which makes the order
+// total, so that pages neither repeat nor skip documents when others are inserted between requests.
+type PageQuery struct {
+	SortField string
+	SortDesc  bool
+	Limit     int64
+	// After is the cursor of the previous page, or nil for the first page.
+	After *contract.Cursor
+}
+
+// Filter returns the filter, restricted to the documents after the cursor. It returns
+// contract.ErrInvalidCursor when the cursor belongs to a list sorted by another field.
+func (q PageQuery) Filter(filter bson.M) (bson.M, error) {
+	if q.After == nil {
+		return filter, nil
+	}
+	if q.After.Sort != q.SortField {
+		return nil, contract.ErrInvalidCursor
+	}
+
+	id, err := bson.ObjectIDFromHex(q.After.ID)
+	if err != nil {
+		return nil, contract.ErrInvalidCursor
+	}
+
+	op := "$gt"
+	if q.SortDesc {
+		op = "$lt"
+	}
+	after := bson.M{"$or": bson.A{
+		bson.M{q.SortField: bson.M{op: q.After.Key}},
+		bson.M{q.SortField: q.After.Key, "_id": bson.M{op: id}},
+	}}
+	if len(filter) == 0 {
+		return after, nil
+	}
+
+	return bson.M{"$and": bson.A{filter, after}}, nil
+}
+
+// FindOptions returns the options sorting the query and fetching one document more than the limit,
+// which tells Page whether another page follows.
+func (q PageQuery) FindOptions() *options.FindOptionsBuilder {
+	order := 1
+	if q.SortDesc {
+		order = -1
+	}
+
+	return options.Find().
+		SetSort(bson.D{{Key: q.SortField, Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(q.Limit + 1)
+}
+
+// Page trims the documents fetched with the query's FindOptions to the limit and returns them with
+// the cursor of the next page, or nil when this is the last page. key returns the value of the sort
+// field and the _id of a document.
+func Page[T any](docs []T, q PageQuery, key func(T) (any, bson.ObjectID)) ([]T, *contract.Cursor) {
+	if int64(len(docs)) <= q.Limit {
+		return docs, nil
+	}
+
+	docs = docs[:q.Limit]
+	sortKey, id := key(docs[len(docs)-1])
+
+	return docs, &contract.Cursor{Sort: q.SortField, Key: sortKey, ID: id.Hex()}
+}
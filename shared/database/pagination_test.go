@@ -0,0 +1,79 @@
+package database_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPageQueryFilter(t *testing.T) {
+	id := bson.NewObjectID()
+	after := &contract.Cursor{Sort: "email", Key: "bob@example.com", ID: id.Hex()}
+
+	t.Run("returns the filter unchanged on the first page", func(t *testing.T) {
+		filter, err := database.PageQuery{SortField: "email"}.Filter(bson.M{"verified": true})
+		if err != nil {
+			t.Fatalf("Filter: %v", err)
+		}
+		if !reflect.DeepEqual(filter, bson.M{"verified": true}) {
+			t.Fatalf("filter is %v, want it unchanged", filter)
+		}
+	})
+
+	t.Run("restricts the filter to the documents after the cursor", func(t *testing.T) {
+		filter, err := database.PageQuery{SortField: "email", SortDesc: true, After: after}.
+			Filter(bson.M{"verified": true})
+		if err != nil {
+			t.Fatalf("Filter: %v", err)
+		}
+
+		want := bson.M{"$and": bson.A{
+			bson.M{"verified": true},
+			bson.M{"$or": bson.A{
+				bson.M{"email": bson.M{"$lt": "bob@example.com"}},
+				bson.M{"email": "bob@example.com", "_id": bson.M{"$lt": id}},
+			}},
+		}}
+		if !reflect.DeepEqual(filter, want) {
+			t.Fatalf("filter is %v, want %v", filter, want)
+		}
+	})
+
+	t.Run("rejects cursors of another order", func(t *testing.T) {
+		_, err := database.PageQuery{SortField: "created_at", After: after}.Filter(bson.M{})
+		if !errors.Is(err, contract.ErrInvalidCursor) {
+			t.Fatalf("Filter error is %v, want ErrInvalidCursor", err)
+		}
+	})
+}
+
+func TestPage(t *testing.T) {
+	type doc struct {
+		ID    bson.ObjectID
+		Email string
+	}
+	docs := []doc{
+		{ID: bson.NewObjectID(), Email: "alice@example.com"},
+		{ID: bson.NewObjectID(), Email: "bob@example.com"},
+		{ID: bson.NewObjectID(), Email: "carol@example.com"},
+	}
+	key := func(d doc) (any, bson.ObjectID) { return d.Email, d.ID }
+
+	page, next := database.Page(docs, database.PageQuery{SortField: "email", Limit: 2}, key)
+	if len(page) != 2 {
+		t.Fatalf("page has %d documents, want 2", len(page))
+	}
+	want := &contract.Cursor{Sort: "email", Key: "bob@example.com", ID: docs[1].ID.Hex()}
+	if !reflect.DeepEqual(next, want) {
+		t.Fatalf("next cursor is %+v, want %+v", next, want)
+	}
+
+	page, next = database.Page(docs, database.PageQuery{SortField: "email", Limit: 3}, key)
+	if len(page) != 3 || next != nil {
+		t.Fatalf("last page has %d documents and cursor %+v, want 3 and none", len(page), next)
+	}
+}
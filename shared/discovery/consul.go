@@ -1,13 +1,19 @@
 package discovery
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/caarlos0/env/v11"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"github.com/vasapolrittideah/moneylog-api/shared/requestid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 	// Required for consul:// resolver to work with gRPC.
 	_ "github.com/mbobakov/grpc-consul-resolver"
@@ -123,13 +129,44 @@ func (r *ConsulRegistry) Deregister(instanceID string, serviceName string) error
 	return nil
 }
 
-// Connect establishes a gRPC connection to a service via Consul with load balancing.
-func (r *ConsulRegistry) Connect(serviceName string) (*grpc.ClientConn, error) {
-	conn, err := grpc.NewClient(
-		fmt.Sprintf("consul://%s/%s?tag=grpc&healthy=true", r.cfg.Addr, serviceName),
+// EnsureRegistered checks that a service instance is registered with the Consul agent and
+// registers it again when it is missing. Consul deregisters instances whose check stays critical
+// for DeregisterAfter, such as while a dependency is down, and nothing else would bring them back.
+func (r *ConsulRegistry) EnsureRegistered(ctx context.Context, instanceID, serviceName, serviceAddr string) error {
+	service, _, err := r.client.Agent().Service(instanceID, (&consulapi.QueryOptions{}).WithContext(ctx))
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if service != nil {
+		return nil
+	}
+
+	r.logger.Warn().
+		Str("serviceName", serviceName).
+		Str("instanceID", instanceID).
+		Msg("Service is not registered, registering it again")
+	return r.Register(instanceID, serviceName, serviceAddr)
+}
+
+// isNotFound reports whether err is the agent's response for an unknown service instance.
+func isNotFound(err error) bool {
+	var statusErr consulapi.StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// Connect establishes a gRPC connection to a service via Consul with load balancing. The options
+// are applied after the defaults, so they may replace the default service config, and their
+// interceptors run after the request ID and metrics interceptors.
+func (r *ConsulRegistry) Connect(serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
-	)
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor(), metrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}, opts...)
+
+	conn, err := grpc.NewClient(fmt.Sprintf("consul://%s/%s?tag=grpc&healthy=true", r.cfg.Addr, serviceName), opts...)
 	if err != nil {
 		return nil, err
 	}
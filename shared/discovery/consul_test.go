@@ -0,0 +1,66 @@
+package discovery_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/shared/discovery"
+)
+
+// fakeAgent serves the Consul agent endpoints for one service instance, which Consul may
+// deregister at any time.
+type fakeAgent struct {
+	mu            sync.Mutex
+	registered    bool
+	registrations int
+}
+
+func (a *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/agent/service/register":
+		a.registered = true
+		a.registrations++
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/agent/service/"):
+		if !a.registered {
+			http.Error(w, "unknown service ID", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ID": "auth-service-1", "Service": "auth-service"}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestEnsureRegistered(t *testing.T) {
+	agent := &fakeAgent{}
+	server := httptest.NewServer(agent)
+	t.Cleanup(server.Close)
+
+	logger := zerolog.Nop()
+	registry, err := discovery.NewConsulRegistry(
+		&discovery.ConsulRegistryConfig{Addr: strings.TrimPrefix(server.URL, "http://")},
+		&logger,
+	)
+	if err != nil {
+		t.Fatalf("NewConsulRegistry: %v", err)
+	}
+
+	ctx := context.Background()
+	for range 2 {
+		if err := registry.EnsureRegistered(ctx, "auth-service-1", "auth-service", "auth:9001"); err != nil {
+			t.Fatalf("EnsureRegistered: %v", err)
+		}
+	}
+	if agent.registrations != 1 {
+		t.Fatalf("instance was registered %d times, want once after Consul dropped it", agent.registrations)
+	}
+}
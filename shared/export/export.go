@@ -0,0 +1,80 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Provider collects the data a service holds about a user for a data export.
+// Each provider contributes one named section to the export archive.
+type Provider interface {
+	Name() string
+	Export(ctx context.Context, userID string) (any, error)
+}
+
+// Archive is the JSON document handed to the user as their data export.
+type Archive struct {
+	UserID      string                     `json:"user_id"`
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Sections    map[string]json.RawMessage `json:"sections"`
+}
+
+// Registry holds the export providers that contribute to a user's archive.
+type Registry struct {
+	mu        sync.RWMutex
+	providers []Provider
+}
+
+// NewRegistry creates a new registry with the given providers.
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{}
+	for _, p := range providers {
+		r.Register(p)
+	}
+
+	return r
+}
+
+// Register adds a provider to the registry.
+func (r *Registry) Register(provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers = append(r.providers, provider)
+}
+
+// Build runs every registered provider and encodes the results into a JSON archive.
+func (r *Registry) Build(ctx context.Context, userID string) ([]byte, error) {
+	r.mu.RLock()
+	providers := make([]Provider, len(r.providers))
+	copy(providers, r.providers)
+	r.mu.RUnlock()
+
+	archive := Archive{
+		UserID:      userID,
+		GeneratedAt: time.Now().UTC(),
+		Sections:    make(map[string]json.RawMessage, len(providers)),
+	}
+
+	for _, p := range providers {
+		if _, ok := archive.Sections[p.Name()]; ok {
+			return nil, fmt.Errorf("duplicate export provider %q", p.Name())
+		}
+
+		data, err := p.Export(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("export provider %q: %w", p.Name(), err)
+		}
+
+		section, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("export provider %q: %w", p.Name(), err)
+		}
+		archive.Sections[p.Name()] = section
+	}
+
+	return json.MarshalIndent(archive, "", "  ")
+}
@@ -0,0 +1,128 @@
+// Package healthcheck checks the health of a service's dependencies and reports it through the
+// gRPC health checking protocol.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check reports whether a dependency is healthy.
+type Check func(ctx context.Context) error
+
+// Checks are checks by dependency name.
+type Checks map[string]Check
+
+// Run runs the checks concurrently, each with the timeout, and returns the error of every
+// dependency by name, with nil for healthy ones.
+func (c Checks) Run(ctx context.Context, timeout time.Duration) map[string]error {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]error, len(c))
+	)
+
+	for name, check := range c {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := check(ctx)
+
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// GRPCCheck checks that the service served on conn reports SERVING through the gRPC health
+// checking protocol. An empty service checks the server as a whole.
+func GRPCCheck(conn grpc.ClientConnInterface, service string) Check {
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+			return errors.New("service is " + resp.GetStatus().String())
+		}
+
+		return nil
+	}
+}
+
+// Monitor periodically runs checks and sets the overall serving status of a gRPC health server
+// to SERVING when all of them pass and NOT_SERVING otherwise.
+type Monitor struct {
+	server   *health.Server
+	checks   Checks
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zerolog.Logger
+}
+
+// NewMonitor creates a monitor that runs the checks every interval, each with the timeout.
+func NewMonitor(
+	server *health.Server,
+	checks Checks,
+	interval, timeout time.Duration,
+	logger *zerolog.Logger,
+) *Monitor {
+	return &Monitor{
+		server:   server,
+		checks:   checks,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+	}
+}
+
+// Run checks the dependencies immediately and then every interval until ctx is done. Once the
+// health server is shut down its status is no longer changed.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	serving := false
+	for {
+		healthy := true
+		for name, err := range m.checks.Run(ctx, m.timeout) {
+			if err != nil {
+				healthy = false
+				m.logger.Warn().Err(err).Str("dependency", name).Msg("Health check failed")
+			}
+		}
+
+		if healthy != serving {
+			serving = healthy
+			status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			if serving {
+				status = grpc_health_v1.HealthCheckResponse_SERVING
+			}
+			m.server.SetServingStatus("", status)
+			m.logger.Info().Str("status", status.String()).Msg("Changed serving status")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package healthcheck_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/healthcheck"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func servingStatus(t *testing.T, server *health.Server) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	return resp.GetStatus()
+}
+
+func waitForStatus(t *testing.T, server *health.Server, want grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for servingStatus(t, server) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("status is %s, want %s", servingStatus(t, server), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestChecksRun(t *testing.T) {
+	failure := errors.New("unreachable")
+	results := healthcheck.Checks{
+		"healthy":   func(context.Context) error { return nil },
+		"unhealthy": func(context.Context) error { return failure },
+		"slow": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}.Run(context.Background(), 10*time.Millisecond)
+
+	if err := results["healthy"]; err != nil {
+		t.Errorf("healthy check failed: %v", err)
+	}
+	if err := results["unhealthy"]; !errors.Is(err, failure) {
+		t.Errorf("unhealthy check returned %v, want %v", err, failure)
+	}
+	if err := results["slow"]; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow check returned %v, want it to time out", err)
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	var healthy atomic.Bool
+	server := health.NewServer()
+	server.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	monitor := healthcheck.NewMonitor(server, healthcheck.Checks{
+		"dependency": func(context.Context) error {
+			if healthy.Load() {
+				return nil
+			}
+			return errors.New("unreachable")
+		},
+	}, time.Millisecond, time.Second, logger.Get())
+	go monitor.Run(ctx)
+
+	healthy.Store(true)
+	waitForStatus(t, server, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	healthy.Store(false)
+	waitForStatus(t, server, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	healthy.Store(true)
+	waitForStatus(t, server, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	server.Shutdown()
+	waitForStatus(t, server, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+}
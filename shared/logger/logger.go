@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/rs/zerolog"
@@ -15,3 +16,17 @@ var globalLogger = zerolog.New(os.Stdout).
 func Get() *zerolog.Logger {
 	return &globalLogger
 }
+
+// WithContext returns a copy of ctx carrying the logger.
+func WithContext(ctx context.Context, l *zerolog.Logger) context.Context {
+	return l.WithContext(ctx)
+}
+
+// FromContext returns the logger carried by ctx, or the global logger when there is none.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return l
+	}
+
+	return &globalLogger
+}
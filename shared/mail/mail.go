@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/rs/zerolog"
+)
+
+// Message is a plain-text email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig contains SMTP connection configuration.
+type SMTPConfig struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     int    `env:"SMTP_PORT"     envDefault:"587"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	From     string `env:"SMTP_FROM"`
+}
+
+// NewSMTPConfig creates a new SMTP configuration from environment variables.
+func NewSMTPConfig(logger *zerolog.Logger) *SMTPConfig {
+	cfg, err := env.ParseAs[SMTPConfig]()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to parse env")
+	}
+
+	return &cfg
+}
+
+// NewMailer creates an SMTP mailer, or a mailer that only logs messages when no SMTP host is configured.
+func NewMailer(cfg *SMTPConfig, logger *zerolog.Logger) Mailer {
+	if cfg.Host == "" {
+		logger.Warn().Msg("SMTP host is not configured, emails will only be logged")
+		return &LogMailer{logger: logger}
+	}
+
+	return &SMTPMailer{cfg: cfg}
+}
+
+// SMTPMailer sends email through an SMTP server.
+type SMTPMailer struct {
+	cfg *SMTPConfig
+}
+
+func (m *SMTPMailer) Send(_ context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, []byte(b.String()))
+}
+
+// LogMailer writes messages to the logger instead of sending them. It is intended for development.
+type LogMailer struct {
+	logger *zerolog.Logger
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Info().
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("Email not sent, SMTP is not configured")
+	return nil
+}
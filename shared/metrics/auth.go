@@ -0,0 +1,46 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	signUps = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "signups_total",
+		Help:      "Number of accounts created.",
+	})
+
+	logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Number of login attempts by outcome.",
+	}, []string{"outcome"})
+
+	activeSessions = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "active_sessions",
+		Help:      "Number of sessions that are neither revoked nor expired.",
+	})
+)
+
+// RecordSignUp counts a newly created account.
+func RecordSignUp() {
+	signUps.Inc()
+}
+
+// RecordLogin counts a successful login.
+func RecordLogin() {
+	logins.WithLabelValues("success").Inc()
+}
+
+// RecordFailedLogin counts a rejected login attempt.
+func RecordFailedLogin() {
+	logins.WithLabelValues("failure").Inc()
+}
+
+// SetActiveSessions sets the number of active sessions.
+func SetActiveSessions(count int64) {
+	activeSessions.Set(float64(count))
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcServerHandlingDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_server",
+		Name:      "handling_seconds",
+		Help:      "Duration of unary gRPC calls handled by the server by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	grpcServerStreamsHandled = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_server",
+		Name:      "streams_handled_total",
+		Help:      "Number of streaming gRPC calls handled by the server by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcClientHandlingDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "handling_seconds",
+		Help:      "Duration of unary gRPC calls made by the client by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	grpcClientCircuitBreakerState = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "circuit_breaker_state",
+		Help:      "State of the circuit breaker of calls to a method of a service: 0 closed, 1 half-open, 2 open.",
+	}, []string{"target", "method"})
+)
+
+// SetCircuitBreakerState records the state of the circuit breaker of calls to method of target.
+func SetCircuitBreakerState(target, method string, state int) {
+	grpcClientCircuitBreakerState.WithLabelValues(target, method).Set(float64(state))
+}
+
+// UnaryServerInterceptor records the duration and status code of every unary call handled by the server.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		grpcServerHandlingDuration.
+			WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts every streaming call handled by the server by its status code once
+// it ends. Their durations are left out of the handling histogram, since streams stay open for as
+// long as the client listens.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		grpcServerStreamsHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor records the duration and status code of every unary call made by the client.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		grpcClientHandlingDuration.
+			WithLabelValues(method, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
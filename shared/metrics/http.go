@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var httpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "Duration of HTTP requests by method, route and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+var deprecatedRequests = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "http",
+	Name:      "deprecated_requests_total",
+	Help:      "Number of requests to deprecated routes by method, route and client.",
+}, []string{"method", "route", "client"})
+
+var realtimeConnections = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Subsystem: "http",
+	Name:      "realtime_connections",
+	Help:      "Number of open realtime event streams by transport.",
+}, []string{"transport"})
+
+// ObserveHTTPRequest records an HTTP request. The route must be the registered route pattern rather
+// than the request path, so that path parameters do not create a new series per request.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// RecordDeprecatedRequest counts a request to a deprecated route. The client should identify the
+// calling application and its version from a bounded set of values, so that the series show which
+// releases still call the route without growing with each User-Agent.
+func RecordDeprecatedRequest(method, route, client string) {
+	deprecatedRequests.WithLabelValues(method, route, client).Inc()
+}
+
+// RealtimeConnectionOpened counts an open realtime event stream of the transport, such as websocket
+// or sse. RealtimeConnectionClosed must be called when it closes.
+func RealtimeConnectionOpened(transport string) {
+	realtimeConnections.WithLabelValues(transport).Inc()
+}
+
+// RealtimeConnectionClosed counts a realtime event stream of the transport as closed.
+func RealtimeConnectionClosed(transport string) {
+	realtimeConnections.WithLabelValues(transport).Dec()
+}
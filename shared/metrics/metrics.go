@@ -0,0 +1,68 @@
+// Package metrics exposes Prometheus metrics for the services.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+)
+
+const (
+	namespace = "moneylog"
+
+	metricsServerReadHeaderTimeout = 5 * time.Second
+)
+
+// Registry holds every metric exposed by this package together with the Go runtime and process collectors.
+var Registry = newRegistry()
+
+var factory = promauto.With(Registry)
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+// MetricsConfig contains metrics endpoint configuration.
+type MetricsConfig struct {
+	Addr string `env:"METRICS_ADDR" envDefault:"0.0.0.0:9100"`
+}
+
+// NewMetricsConfig creates a new metrics configuration from environment variables.
+func NewMetricsConfig(logger *zerolog.Logger) *MetricsConfig {
+	cfg, err := env.ParseAs[MetricsConfig]()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to parse env")
+	}
+
+	return &cfg
+}
+
+// Handler returns an HTTP handler that serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// NewServer creates an HTTP server that serves the metrics on /metrics. It is kept apart from the
+// service's own listener so that metrics are never exposed through the public load balancer.
+func NewServer(cfg *MetricsConfig) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: metricsServerReadHeaderTimeout,
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	mongoCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "command_duration_seconds",
+		Help:      "Duration of MongoDB commands by command name and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "outcome"})
+
+	mongoPoolConnections = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "pool_connections",
+		Help:      "Number of open connections in the MongoDB connection pool by server address.",
+	}, []string{"address"})
+
+	mongoPoolConnectionsInUse = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "pool_connections_in_use",
+		Help:      "Number of connections checked out of the MongoDB connection pool by server address.",
+	}, []string{"address"})
+
+	mongoPoolCheckOutFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "pool_checkout_failures_total",
+		Help:      "Number of failed attempts to check a connection out of the MongoDB connection pool.",
+	}, []string{"address", "reason"})
+)
+
+// ObserveMongoCommand records a MongoDB command that either succeeded or failed.
+func ObserveMongoCommand(command string, failed bool, duration time.Duration) {
+	outcome := "success"
+	if failed {
+		outcome = "failure"
+	}
+
+	mongoCommandDuration.WithLabelValues(command, outcome).Observe(duration.Seconds())
+}
+
+// MongoConnectionOpened records a connection added to the pool for the server.
+func MongoConnectionOpened(address string) {
+	mongoPoolConnections.WithLabelValues(address).Inc()
+}
+
+// MongoConnectionClosed records a connection removed from the pool for the server.
+func MongoConnectionClosed(address string) {
+	mongoPoolConnections.WithLabelValues(address).Dec()
+}
+
+// MongoConnectionCheckedOut records a connection handed out to an operation.
+func MongoConnectionCheckedOut(address string) {
+	mongoPoolConnectionsInUse.WithLabelValues(address).Inc()
+}
+
+// MongoConnectionCheckedIn records a connection returned to the pool by an operation.
+func MongoConnectionCheckedIn(address string) {
+	mongoPoolConnectionsInUse.WithLabelValues(address).Dec()
+}
+
+// MongoConnectionCheckOutFailed records an operation that could not get a connection from the pool.
+func MongoConnectionCheckOutFailed(address, reason string) {
+	mongoPoolCheckOutFailures.WithLabelValues(address, reason).Inc()
+}
@@ -0,0 +1,142 @@
+// Package requestid propagates a per-request correlation ID across HTTP, gRPC and log lines.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/vasapolrittideah/moneylog-api/shared/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key carrying the request ID.
+	MetadataKey = "x-request-id"
+	// LogField is the log field the request ID is written to.
+	LogField = "request_id"
+
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New generates a new request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether a client supplied request ID is safe to accept and propagate.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID and a logger that writes it on every line.
+func NewContext(ctx context.Context, id string, base *zerolog.Logger) context.Context {
+	l := base.With().Str(LogField, id).Logger()
+	ctx = context.WithValue(ctx, contextKey{}, id)
+
+	return logger.WithContext(ctx, &l)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty string when there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// UnaryClientInterceptor forwards the request ID in ctx to the server as gRPC metadata.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if id := FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor forwards the request ID in ctx to the server as gRPC metadata when a
+// stream is opened.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if id := FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor reads the request ID from the incoming gRPC metadata, generating one
+// when the caller did not send it, and attaches it with a request-scoped logger to the context.
+func UnaryServerInterceptor(base *zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(incomingContext(ctx, base), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor: the context of
+// the stream carries the request ID and the request-scoped logger.
+func StreamServerInterceptor(base *zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: incomingContext(ss.Context(), base)})
+	}
+}
+
+// serverStream is a grpc.ServerStream with a replaced context.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// incomingContext attaches the request ID of the incoming gRPC metadata, or a new one when the
+// caller did not send it, to ctx.
+func incomingContext(ctx context.Context, base *zerolog.Logger) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 && Valid(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = New()
+	}
+
+	return NewContext(ctx, id, base)
+}
@@ -0,0 +1,212 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/contract"
+	"github.com/vasapolrittideah/moneylog-api/shared/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateHalfOpen lets a single trial call through to find out whether the service recovered.
+	StateHalfOpen
+	// StateOpen fails every call without calling the service.
+	StateOpen
+)
+
+// CircuitBreaker stops calling a method of a service after consecutive failures that indicate it is
+// unhealthy, failing fast with Unavailable until a trial call after openTimeout succeeds.
+type CircuitBreaker struct {
+	target           string
+	method           string
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trialing bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker for calls to method of target.
+func NewCircuitBreaker(target, method string, failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	metrics.SetCircuitBreakerState(target, method, int(StateClosed))
+
+	return &CircuitBreaker{
+		target:           target,
+		method:           method,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// MethodCircuitBreakers keeps a circuit breaker per method of a service, so that a failing method,
+// such as a slow export, does not fail fast the calls to the others.
+type MethodCircuitBreakers struct {
+	target           string
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewMethodCircuitBreakers creates the circuit breakers for calls to target. The breaker of a
+// method is created closed on its first call.
+func NewMethodCircuitBreakers(
+	target string,
+	failureThreshold int,
+	openTimeout time.Duration,
+) *MethodCircuitBreakers {
+	return &MethodCircuitBreakers{
+		target:           target,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		breakers:         make(map[string]*CircuitBreaker),
+	}
+}
+
+// UnaryClientInterceptor applies the circuit breaker of the called method.
+func (m *MethodCircuitBreakers) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return m.Breaker(method).UnaryClientInterceptor()(ctx, method, req, reply, cc, invoker, opts...)
+	}
+}
+
+// Breaker returns the circuit breaker of method, given by full name.
+func (m *MethodCircuitBreakers) Breaker(method string) *CircuitBreaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	breaker, ok := m.breakers[method]
+	if !ok {
+		breaker = NewCircuitBreaker(m.target, method, m.failureThreshold, m.openTimeout)
+		m.breakers[method] = breaker
+	}
+
+	return breaker
+}
+
+// UnaryClientInterceptor fails calls with Unavailable while the circuit is open and records the
+// outcome of the calls it lets through.
+func (b *CircuitBreaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if retryAfter, ok := b.allow(); !ok {
+			return contract.NewGRPCError(
+				codes.Unavailable,
+				contract.ErrorCodeUnavailable,
+				"service is temporarily unavailable",
+				contract.NewRetryInfo(retryAfter),
+			)
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(err)
+
+		return err
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow reports whether a call may go through, and otherwise how long the circuit stays open.
+func (b *CircuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		remaining := b.openTimeout - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return remaining, false
+		}
+		b.setState(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.trialing {
+			return b.openTimeout, false
+		}
+		b.trialing = true
+	}
+
+	return 0, true
+}
+
+// record updates the circuit with the outcome of a call. Calls canceled by the caller say nothing
+// about the health of the service and are ignored.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialing = false
+
+	if status.Code(err) == codes.Canceled {
+		return
+	}
+	if !isFailure(err) {
+		b.failures = 0
+		b.setState(StateClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+		b.setState(StateOpen)
+	}
+}
+
+// setState moves the circuit to state. It must be called with b.mu held.
+func (b *CircuitBreaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	metrics.SetCircuitBreakerState(b.target, b.method, int(state))
+}
+
+// isFailure reports whether err indicates that the service, rather than the request, is at fault.
+// ResourceExhausted is not a failure: the client returns it for messages over its size limit, and
+// services for callers over their quota.
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+// Package resilience protects gRPC clients from slow or unhealthy services with per-method
+// deadlines, retries of idempotent methods and a circuit breaker per method.
+package resilience
+
+import (
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// ClientConfig contains gRPC client resilience configuration.
+type ClientConfig struct {
+	// Timeout is the deadline of calls to methods without an entry in MethodTimeouts.
+	Timeout time.Duration `env:"GRPC_CLIENT_TIMEOUT" envDefault:"5s"`
+	// MethodTimeouts maps method names, either full ("/auth.v1.AuthService/Login") or short
+	// ("Login"), to their deadline, e.g. "Login:3s,ExportUserData:10s".
+	MethodTimeouts map[string]time.Duration `env:"GRPC_CLIENT_METHOD_TIMEOUTS"`
+
+	RetryMaxAttempts    int           `env:"GRPC_CLIENT_RETRY_MAX_ATTEMPTS"    envDefault:"3"`
+	RetryInitialBackoff time.Duration `env:"GRPC_CLIENT_RETRY_INITIAL_BACKOFF" envDefault:"100ms"`
+	RetryMaxBackoff     time.Duration `env:"GRPC_CLIENT_RETRY_MAX_BACKOFF"     envDefault:"1s"`
+
+	// BreakerFailureThreshold is the number of consecutive failures of a method that opens its circuit.
+	BreakerFailureThreshold int `env:"GRPC_CLIENT_BREAKER_FAILURE_THRESHOLD" envDefault:"5"`
+	// BreakerOpenTimeout is how long the circuit stays open before a trial call is let through.
+	BreakerOpenTimeout time.Duration `env:"GRPC_CLIENT_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+}
+
+// NewClientConfig creates a new gRPC client resilience configuration from environment variables.
+func NewClientConfig(logger *zerolog.Logger) *ClientConfig {
+	cfg, err := env.ParseAs[ClientConfig]()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to parse env")
+	}
+
+	return &cfg
+}
+
+// DialOptions returns the dial options that apply the configuration to a connection to target.
+// Only the idempotent methods, given by full name, are retried.
+func DialOptions(target string, cfg *ClientConfig, idempotentMethods ...string) []grpc.DialOption {
+	breakers := NewMethodCircuitBreakers(target, cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout)
+
+	return []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(ServiceConfig(cfg, idempotentMethods...)),
+		grpc.WithChainUnaryInterceptor(
+			UnaryTimeoutInterceptor(cfg.Timeout, cfg.MethodTimeouts),
+			breakers.UnaryClientInterceptor(),
+		),
+	}
+}
@@ -0,0 +1,156 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vasapolrittideah/moneylog-api/shared/clocktest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// call invokes the breaker's interceptor with an invoker that returns err.
+func call(b *CircuitBreaker, err error) (bool, error) {
+	var invoked bool
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked = true
+		return err
+	}
+
+	return invoked, b.UnaryClientInterceptor()(context.Background(), "/test.v1.Test/Call", nil, nil, nil, invoker)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("opens after consecutive failures and fails fast", func(t *testing.T) {
+		b := NewCircuitBreaker("test", "/test.v1.Test/Call", 2, time.Minute)
+
+		_, _ = call(b, unavailable)
+		_, _ = call(b, unavailable)
+		if b.State() != StateOpen {
+			t.Fatalf("state is %d, want open", b.State())
+		}
+
+		invoked, err := call(b, nil)
+		if invoked {
+			t.Fatal("the service was called while the circuit was open")
+		}
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("code is %s, want Unavailable", status.Code(err))
+		}
+	})
+
+	t.Run("ignores client errors and resets on success", func(t *testing.T) {
+		b := NewCircuitBreaker("test", "/test.v1.Test/Call", 2, time.Minute)
+
+		_, _ = call(b, unavailable)
+		_, _ = call(b, status.Error(codes.NotFound, "not found"))
+		_, _ = call(b, unavailable)
+		_, _ = call(b, status.Error(codes.ResourceExhausted, "message larger than max"))
+		if b.State() != StateClosed {
+			t.Fatalf("state is %d, want closed", b.State())
+		}
+	})
+
+	t.Run("closes after a successful trial call", func(t *testing.T) {
+		clock := clocktest.New()
+		b := NewCircuitBreaker("test", "/test.v1.Test/Call", 1, time.Minute)
+		b.now = clock.Now
+
+		_, _ = call(b, unavailable)
+		clock.Advance(time.Minute)
+
+		if invoked, _ := call(b, nil); !invoked {
+			t.Fatal("the trial call was not let through")
+		}
+		if b.State() != StateClosed {
+			t.Fatalf("state is %d, want closed", b.State())
+		}
+	})
+
+	t.Run("reopens after a failed trial call", func(t *testing.T) {
+		clock := clocktest.New()
+		b := NewCircuitBreaker("test", "/test.v1.Test/Call", 3, time.Minute)
+		b.now = clock.Now
+
+		for range 3 {
+			_, _ = call(b, unavailable)
+		}
+		clock.Advance(time.Minute)
+
+		_, _ = call(b, unavailable)
+		if b.State() != StateOpen {
+			t.Fatalf("state is %d, want open", b.State())
+		}
+	})
+}
+
+func TestMethodCircuitBreakers(t *testing.T) {
+	breakers := NewMethodCircuitBreakers("test", 1, time.Minute)
+	interceptor := breakers.UnaryClientInterceptor()
+
+	invoked := map[string]int{}
+	invoker := func(_ context.Context, method string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		invoked[method]++
+		if method == "/test.v1.Test/Export" {
+			return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		}
+		return nil
+	}
+
+	for range 2 {
+		for _, method := range []string{"/test.v1.Test/Export", "/test.v1.Test/Get"} {
+			_ = interceptor(context.Background(), method, nil, nil, nil, invoker)
+		}
+	}
+
+	if breakers.Breaker("/test.v1.Test/Export").State() != StateOpen {
+		t.Error("the circuit of the failing method is not open")
+	}
+	if breakers.Breaker("/test.v1.Test/Get").State() != StateClosed {
+		t.Error("the circuit of the healthy method is not closed")
+	}
+	if invoked["/test.v1.Test/Export"] != 1 || invoked["/test.v1.Test/Get"] != 2 {
+		t.Errorf("methods were called %v times, want Export once and Get twice", invoked)
+	}
+}
+
+func TestServiceConfig(t *testing.T) {
+	cfg := &ClientConfig{
+		RetryMaxAttempts:    3,
+		RetryInitialBackoff: 100 * time.Millisecond,
+		RetryMaxBackoff:     time.Second,
+	}
+
+	conn, err := grpc.NewClient(
+		"passthrough:///localhost:0",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(ServiceConfig(cfg, "/test.v1.Test/Get")),
+	)
+	if err != nil {
+		t.Fatalf("service config was rejected: %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestMethodTimeout(t *testing.T) {
+	timeouts := map[string]time.Duration{
+		"/test.v1.Test/Export": 10 * time.Second,
+		"Login":                3 * time.Second,
+	}
+
+	tests := map[string]time.Duration{
+		"/test.v1.Test/Export": 10 * time.Second,
+		"/test.v1.Test/Login":  3 * time.Second,
+		"/test.v1.Test/Get":    time.Second,
+	}
+	for method, want := range tests {
+		if got := methodTimeout(method, time.Second, timeouts); got != want {
+			t.Errorf("timeout of %s is %s, want %s", method, got, want)
+		}
+	}
+}
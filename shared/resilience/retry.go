@@ -0,0 +1,70 @@
+package resilience
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// backoffMultiplier is the factor the retry backoff grows by after each attempt.
+const backoffMultiplier = 2
+
+type serviceConfig struct {
+	LoadBalancingPolicy string         `json:"loadBalancingPolicy"`
+	MethodConfig        []methodConfig `json:"methodConfig,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy retryPolicy  `json:"retryPolicy"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// ServiceConfig returns a gRPC service config that balances calls round robin across instances
+// and retries the idempotent methods, given by full name, when the service is unavailable.
+func ServiceConfig(cfg *ClientConfig, idempotentMethods ...string) string {
+	config := serviceConfig{LoadBalancingPolicy: "round_robin"}
+
+	if len(idempotentMethods) > 0 && cfg.RetryMaxAttempts > 1 {
+		names := make([]methodName, 0, len(idempotentMethods))
+		for _, method := range idempotentMethods {
+			service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+			names = append(names, methodName{Service: service, Method: name})
+		}
+
+		config.MethodConfig = []methodConfig{{
+			Name: names,
+			RetryPolicy: retryPolicy{
+				MaxAttempts:          cfg.RetryMaxAttempts,
+				InitialBackoff:       seconds(cfg.RetryInitialBackoff.Seconds()),
+				MaxBackoff:           seconds(cfg.RetryMaxBackoff.Seconds()),
+				BackoffMultiplier:    backoffMultiplier,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		panic(fmt.Sprintf("resilience: failed to marshal service config: %v", err))
+	}
+
+	return string(b)
+}
+
+// seconds formats a duration as the service config expects, e.g. "0.1s".
+func seconds(s float64) string {
+	return fmt.Sprintf("%gs", s)
+}
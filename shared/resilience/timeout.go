@@ -0,0 +1,50 @@
+package resilience
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryTimeoutInterceptor sets the deadline of every call to the timeout of its method, falling
+// back to defaultTimeout. Deadlines that the caller already set earlier are kept.
+func UnaryTimeoutInterceptor(
+	defaultTimeout time.Duration,
+	methodTimeouts map[string]time.Duration,
+) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		timeout := methodTimeout(method, defaultTimeout, methodTimeouts)
+		if timeout <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func methodTimeout(
+	method string,
+	defaultTimeout time.Duration,
+	methodTimeouts map[string]time.Duration,
+) time.Duration {
+	if timeout, ok := methodTimeouts[method]; ok {
+		return timeout
+	}
+	if timeout, ok := methodTimeouts[method[strings.LastIndex(method, "/")+1:]]; ok {
+		return timeout
+	}
+
+	return defaultTimeout
+}
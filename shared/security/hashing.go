@@ -4,7 +4,9 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -46,21 +48,27 @@ func VerifyPassword(password, encodedHash string) (bool, error) {
 
 // parseHash parses an Argon2 hash into its salt and hash components.
 func parseHash(encodedHash string) ([]byte, []byte, error) {
-	var version, m, t, p int
-	var saltStr, hashStr string
+	// The salt and hash are split on "$" rather than scanned, since %s would consume the "$"
+	// between them.
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return nil, nil, errors.New("invalid password hash")
+	}
 
-	_, err := fmt.Sscanf(encodedHash, "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
-		&version, &m, &t, &p, &saltStr, &hashStr)
-	if err != nil {
+	var version, m, t, p int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, err
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &m, &t, &p); err != nil {
 		return nil, nil, err
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(saltStr)
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
 		return nil, nil, err
 	}
 
-	hash, err := base64.RawStdEncoding.DecodeString(hashStr)
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,92 @@
+// Package tracing configures the OpenTelemetry tracer provider shared by all services.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// TracingConfig contains trace exporter configuration.
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER"      envDefault:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO"  envDefault:"1"`
+}
+
+// NewTracingConfig creates a new tracing configuration from environment variables.
+func NewTracingConfig(logger *zerolog.Logger) *TracingConfig {
+	cfg, err := env.ParseAs[TracingConfig]()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to parse env")
+	}
+
+	return &cfg
+}
+
+// NewTracerProvider creates a tracer provider that exports spans with the configured exporter and
+// installs it, together with the W3C trace context propagator, as the global default. The caller
+// must shut the provider down to flush pending spans.
+func NewTracerProvider(ctx context.Context, serviceName string, cfg *TracingConfig) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	switch cfg.Exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp, nil
+}
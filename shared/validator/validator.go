@@ -0,0 +1,162 @@
+// Package validator provides validate tags for domain payloads that go-playground/validator does not
+// have, such as money amounts, calendar dates and MongoDB ObjectIDs. Register them on a validator
+// with RegisterValidations. Money amounts are decimal strings, never floats, so that they are not
+// rounded on their way to the services.
+package validator
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// TagDecimal accepts a decimal string with at most the scale given as its parameter in
+	// fractional digits, e.g. decimal=2 accepts "-12.50" but not "12.505", "1e3" or 12.5.
+	TagDecimal = "decimal"
+	// TagPositiveAmount accepts a decimal string or an integer greater than zero.
+	TagPositiveAmount = "positive_amount"
+	// TagDate accepts a calendar date string in the YYYY-MM-DD format.
+	TagDate = "date"
+	// TagDateGTEField accepts a date string that is not before the date in the field given as its
+	// parameter, e.g. `validate:"date,date_gtefield=StartDate"` on an end date.
+	TagDateGTEField = "date_gtefield"
+	// TagObjectID accepts the hex string of a MongoDB ObjectID.
+	TagObjectID = "objectid"
+	// TagHexColor accepts a #RRGGBB color code, as used for categories.
+	TagHexColor = "hex_color"
+
+	// DateLayout is the layout of date strings.
+	DateLayout = time.DateOnly
+)
+
+var hexColorRegex = regexp.MustCompile("^#[0-9a-fA-F]{6}$")
+
+// RegisterValidations registers the custom tags on v. Currency codes and time zone names are
+// validated with the built-in iso4217 and timezone tags.
+func RegisterValidations(v *validator.Validate) error {
+	validations := map[string]validator.Func{
+		TagDecimal:        isDecimal,
+		TagPositiveAmount: isPositiveAmount,
+		TagDate:           isDate,
+		TagDateGTEField:   isDateGTEField,
+		TagObjectID:       isObjectID,
+		TagHexColor:       isHexColor,
+	}
+
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isDecimal(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+
+	scale, err := strconv.Atoi(fl.Param())
+	if err != nil || scale < 0 {
+		return false
+	}
+
+	return validDecimal(fl.Field().String(), scale)
+}
+
+func isPositiveAmount(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		amount := field.String()
+		return validDecimal(amount, -1) && !strings.HasPrefix(amount, "-") &&
+			strings.ContainsAny(amount, "123456789")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() > 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() > 0
+	default:
+		return false
+	}
+}
+
+// validDecimal reports whether s is an optionally negative decimal number with at most scale
+// fractional digits, or any number of them when scale is negative.
+func validDecimal(s string, scale int) bool {
+	integer, fraction, hasFraction := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if !digits(integer) || (hasFraction && !digits(fraction)) {
+		return false
+	}
+
+	return scale < 0 || len(fraction) <= scale
+}
+
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDate(fl validator.FieldLevel) bool {
+	_, ok := parseDate(fl.Field())
+	return ok
+}
+
+func isDateGTEField(fl validator.FieldLevel) bool {
+	date, ok := parseDate(fl.Field())
+	if !ok {
+		return false
+	}
+
+	// The other field is validated on its own, so a missing or invalid start does not fail the end.
+	other, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !found {
+		return true
+	}
+	otherDate, ok := parseDate(other)
+	if !ok {
+		return true
+	}
+
+	return !date.Before(otherDate)
+}
+
+func parseDate(field reflect.Value) (time.Time, bool) {
+	if field.Kind() != reflect.String {
+		return time.Time{}, false
+	}
+
+	date, err := time.Parse(DateLayout, field.String())
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+func isObjectID(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+
+	_, err := bson.ObjectIDFromHex(fl.Field().String())
+	return err == nil
+}
+
+func isHexColor(fl validator.FieldLevel) bool {
+	return fl.Field().Kind() == reflect.String && hexColorRegex.MatchString(fl.Field().String())
+}
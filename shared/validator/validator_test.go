@@ -0,0 +1,95 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	sharedvalidator "github.com/vasapolrittideah/moneylog-api/shared/validator"
+)
+
+func newValidate(t *testing.T) *validator.Validate {
+	t.Helper()
+
+	v := validator.New()
+	if err := sharedvalidator.RegisterValidations(v); err != nil {
+		t.Fatalf("register validations: %v", err)
+	}
+
+	return v
+}
+
+func TestValidations(t *testing.T) {
+	v := newValidate(t)
+
+	tests := []struct {
+		tag   string
+		value any
+		valid bool
+	}{
+		{tag: "decimal=2", value: "12.50", valid: true},
+		{tag: "decimal=2", value: "-3", valid: true},
+		{tag: "decimal=2", value: "0.5", valid: true},
+		{tag: "decimal=2", value: "12.505", valid: false},
+		{tag: "decimal=2", value: "1e3", valid: false},
+		{tag: "decimal=2", value: ".5", valid: false},
+		{tag: "decimal=2", value: "5.", valid: false},
+		{tag: "decimal=2", value: "+5", valid: false},
+		{tag: "decimal=0", value: "10", valid: true},
+		{tag: "decimal=0", value: "10.0", valid: false},
+		{tag: "decimal=2", value: 12.5, valid: false},
+		{tag: "positive_amount", value: "0.01", valid: true},
+		{tag: "positive_amount", value: "0.00", valid: false},
+		{tag: "positive_amount", value: "-1", valid: false},
+		{tag: "positive_amount", value: 100, valid: true},
+		{tag: "positive_amount", value: 0, valid: false},
+		{tag: "positive_amount", value: 1.5, valid: false},
+		{tag: "date", value: "2026-02-28", valid: true},
+		{tag: "date", value: "2026-02-30", valid: false},
+		{tag: "date", value: "28/02/2026", valid: false},
+		{tag: "objectid", value: "6566f1b2c3d4e5f6a7b8c9d0", valid: true},
+		{tag: "objectid", value: "6566f1b2c3d4e5f6a7b8c9", valid: false},
+		{tag: "objectid", value: "zzzzzzzzzzzzzzzzzzzzzzzz", valid: false},
+		{tag: "hex_color", value: "#1A2b3C", valid: true},
+		{tag: "hex_color", value: "#fff", valid: false},
+		{tag: "hex_color", value: "1a2b3c", valid: false},
+		{tag: "iso4217", value: "THB", valid: true},
+		{tag: "iso4217", value: "thb", valid: false},
+		{tag: "timezone", value: "Asia/Bangkok", valid: true},
+		{tag: "timezone", value: "Asia/Nowhere", valid: false},
+	}
+
+	for _, tt := range tests {
+		err := v.Var(tt.value, tt.tag)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%s on %#v: valid = %v, want %v (err: %v)", tt.tag, tt.value, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestDateGTEField(t *testing.T) {
+	type dateRange struct {
+		StartDate string `validate:"required,date"`
+		EndDate   string `validate:"required,date,date_gtefield=StartDate"`
+	}
+
+	v := newValidate(t)
+
+	tests := []struct {
+		name  string
+		input dateRange
+		valid bool
+	}{
+		{name: "after start", input: dateRange{StartDate: "2026-01-01", EndDate: "2026-01-31"}, valid: true},
+		{name: "same day", input: dateRange{StartDate: "2026-01-01", EndDate: "2026-01-01"}, valid: true},
+		{name: "before start", input: dateRange{StartDate: "2026-01-31", EndDate: "2026-01-01"}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.input)
+			if valid := err == nil; valid != tt.valid {
+				t.Errorf("valid = %v, want %v (err: %v)", valid, tt.valid, err)
+			}
+		})
+	}
+}